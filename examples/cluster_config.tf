@@ -0,0 +1,7 @@
+data "k3d_cluster_config" "k3s-default" {
+  name = "k3s-default"
+}
+
+output "k3s-default-simple-config" {
+  value = data.k3d_cluster_config.k3s-default.simple_config
+}
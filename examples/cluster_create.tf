@@ -15,6 +15,37 @@ resource "k3d_cluster" "sample_cluster" {
   //      "loadbalancer",
   //    ]
   //  }
+  //
+  //  ports {
+  //    host_port = "30000-30010"
+  //    container_port = "30000-30010"
+  //    node_filters = [
+  //      "server:0:direct",
+  //    ]
+  //  }
+  //
+  //  k3s {
+  //    disable = ["traefik", "servicelb"]
+  //    flannel_backend = "host-gw"
+  //  }
+  //
+  //  node_pool {
+  //    name   = "gpu"
+  //    count  = 2
+  //    memory = "4g"
+  //    labels = {
+  //      accelerator = "nvidia"
+  //    }
+  //    taints = ["gpu=true:NoSchedule"]
+  //    env = {
+  //      HTTP_PROXY = "http://proxy:3128"
+  //    }
+  //    volumes {
+  //      source      = "/data"
+  //      destination = "/data"
+  //      mode        = "ro"
+  //    }
+  //  }
 
   k3d_options {
     no_loadbalancer = false
@@ -0,0 +1,8 @@
+resource "k3d_network" "shared" {
+  name    = "k3d-shared"
+  subnet  = "172.28.0.0/16"
+  gateway = "172.28.0.1"
+  labels = {
+    team = "platform"
+  }
+}
@@ -16,4 +16,43 @@ resource "k3d_node" "node-2" {
   // volume = "8g"
   //  wait     = true
   //  timeout  = 3
-}
\ No newline at end of file
+
+  drain {
+    timeout              = "5m"
+    ignore_daemonsets    = true
+    delete_emptydir_data = false
+    grace_period         = 30
+  }
+
+  timeouts {
+    create = "15m"
+  }
+}
+resource "k3d_node" "node-3" {
+  name     = "gpu-node-terraform"
+  cluster  = k3d_cluster.sample_cluster.name
+  role     = "agent"
+  replicas = 1
+
+  k3s_args = ["--kubelet-arg=max-pods=50"]
+  labels = {
+    "node.kubernetes.io/accelerator" = "nvidia"
+  }
+  taints = ["dedicated=gpu:NoSchedule"]
+  env = {
+    "NVIDIA_VISIBLE_DEVICES" = "all"
+  }
+
+  volumes {
+    source      = "/opt/models"
+    destination = "/models"
+    mode        = "ro"
+  }
+}
+
+resource "k3d_node" "control-plane" {
+  name     = "control-plane-terraform"
+  cluster  = k3d_cluster.sample_cluster.name
+  role     = "server"
+  replicas = 2
+}
@@ -0,0 +1,218 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/client"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/k3d/cluster"
+	k3dNode "github.com/nikhilsbhat/terraform-provider-k3d/pkg/k3d/node"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/utils"
+	k3dClient "github.com/rancher/k3d/v5/pkg/client"
+	"github.com/rancher/k3d/v5/pkg/config/types"
+	"github.com/rancher/k3d/v5/pkg/config/v1alpha4"
+	K3D "github.com/rancher/k3d/v5/pkg/types"
+)
+
+func dataSourceClusterConfig() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceClusterConfigRead,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the existing cluster of which the config has to be rebuilt",
+			},
+			"simple_config": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "k3d config of kind `Simple` equivalent to the cluster, which could be passed on to `simple_config` " +
+					"of k3d_cluster or to `k3d cluster create --config`",
+			},
+			"servers_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Count of servers created along with the cluster",
+			},
+			"agents_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Count of agents created along with the cluster",
+			},
+			"image": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Image the nodes of the cluster are running",
+			},
+			"network": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Network the cluster is associated with, set only when the network is not managed by k3d",
+			},
+			"subnetwork": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Subnet of the network the cluster is associated with",
+			},
+			"cluster_token": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Token of the cluster, it is not part of `simple_config`",
+			},
+			"volumes": {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Description: "Volumes mounted into the nodes, except the image volume managed by k3d",
+				Elem: &schema.Resource{
+					Schema: resourceClusterVolumeSchema(),
+				},
+			},
+			"ports": {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Description: "Ports mapped from the node containers to the host, except the port of the kubernetes API",
+				Elem: &schema.Resource{
+					Schema: resourceClusterPortsConfig(),
+				},
+			},
+			"env": {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Description: "Environment variables set on the nodes, except the ones set by the image or k3d",
+				Elem: &schema.Resource{
+					Schema: resourceClusterEnvsAndLabelsSchema(),
+				},
+			},
+			"k3s_options": {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Description: "Extra arguments and node labels passed on to k3s",
+				Elem: &schema.Resource{
+					Schema: resourceClusterK3sOptionsSchema(),
+				},
+			},
+			"kube_api": {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Description: "How the kubernetes API of the cluster is exposed",
+				Elem: &schema.Resource{
+					Schema: resourceClusterKubeAPISchema(),
+				},
+			},
+			"k3d_options": {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Description: "k3d runtime settings the cluster was created with",
+				Elem: &schema.Resource{
+					Schema: resourceClusterK3dOptionsSchema(),
+				},
+			},
+		},
+	}
+}
+
+func dataSourceClusterConfigRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	defaultConfig := meta.(*client.Config)
+
+	clusterName := utils.String(d.Get(utils.TerraformResourceName))
+
+	k3dCluster, err := k3dClient.ClusterGet(ctx, defaultConfig.K3DRuntime, &K3D.Cluster{Name: clusterName})
+	if err != nil {
+		return diag.Errorf("fetching cluster '%s' errored with: %v", clusterName, err)
+	}
+
+	inspector, err := k3dNode.NewInspector(defaultConfig.K3DRuntime)
+	if err != nil {
+		return diag.Errorf("inspecting nodes of cluster '%s' errored with: %v", clusterName, err)
+	}
+
+	defer inspector.Close()
+
+	attributes, err := getClusterAttributes(ctx, inspector, k3dCluster)
+	if err != nil {
+		return diag.Errorf("reading config of cluster '%s' errored with: %v", clusterName, err)
+	}
+
+	attributes[utils.TerraformResourcePorts], err = getClusterPublishedPorts(ctx, inspector, k3dCluster, attributes[utils.TerraformResourcePorts].([]any))
+	if err != nil {
+		return diag.Errorf("reading published ports of cluster '%s' errored with: %v", clusterName, err)
+	}
+
+	attributes[utils.TerraformResourceServersCount] = len(getClusterNodesByRole(k3dCluster, K3D.ServerRole))
+	attributes[utils.TerraformResourceAgentsCount] = len(getClusterNodesByRole(k3dCluster, K3D.AgentRole))
+	attributes[utils.TerraformResourceClusterToken] = k3dCluster.Token
+	attributes[utils.TerraformResourceK3dOptions] = getClusterK3dOptions(k3dCluster)
+
+	if k3dCluster.Network.External {
+		attributes[utils.TerraformResourceNetwork] = k3dCluster.Network.Name
+	}
+
+	for attribute, value := range attributes {
+		if err = d.Set(attribute, value); err != nil {
+			return diag.Errorf("setting %s errored with %v", attribute, err)
+		}
+	}
+
+	simpleConfig, err := getClusterSimpleConfig(d)
+	if err != nil {
+		return diag.Errorf("building %s of cluster '%s' errored with: %v", utils.TerraformSimpleConfig, clusterName, err)
+	}
+
+	content, err := cluster.MarshalSimpleConfig(simpleConfig)
+	if err != nil {
+		return diag.Errorf("marshalling %s of cluster '%s' errored with: %v", utils.TerraformSimpleConfig, clusterName, err)
+	}
+
+	if err = d.Set(utils.TerraformSimpleConfig, content); err != nil {
+		return diag.Errorf("setting %s errored with %v", utils.TerraformSimpleConfig, err)
+	}
+
+	d.SetId(clusterName)
+
+	return nil
+}
+
+// getClusterK3dOptions returns the k3d options that could be identified from the cluster,
+// the ones that only affect the creation of the cluster are left to their defaults.
+func getClusterK3dOptions(k3dCluster *K3D.Cluster) []any {
+	return []any{map[string]any{
+		"wait":            true,
+		"no_loadbalancer": !k3dCluster.HasLoadBalancer(),
+		"no_image_volume": len(k3dCluster.ImageVolume) == 0,
+	}}
+}
+
+// getClusterSimpleConfig builds the SimpleConfig from the attributes, the same way resourceClusterCreate does,
+// except the cluster token which is left out.
+func getClusterSimpleConfig(d *schema.ResourceData) (*v1alpha4.SimpleConfig, error) {
+	k3dOptions, err := flattenK3DOptions(d.Get(utils.TerraformResourceK3dOptions))
+	if err != nil {
+		return nil, err
+	}
+
+	simpleConfig := &v1alpha4.SimpleConfig{
+		ObjectMeta: types.ObjectMeta{
+			Name: utils.String(d.Get(utils.TerraformResourceName)),
+		},
+		Servers:   utils.Int(d.Get(utils.TerraformResourceServersCount)),
+		Agents:    utils.Int(d.Get(utils.TerraformResourceAgentsCount)),
+		Image:     utils.String(d.Get(utils.TerraformResourceImage)),
+		Network:   utils.String(d.Get(utils.TerraformResourceNetwork)),
+		Subnet:    utils.String(d.Get(utils.TerraformResourceSubnet)),
+		ExposeAPI: flattenKubeAPI(d.Get(utils.TerraformKubeAPI)),
+		Volumes:   flattenVolumes(d.Get(utils.TerraformResourceVolumes)),
+		Ports:     flattenPorts(d.Get(utils.TerraformResourcePorts)),
+		Env:       flattenEnvVars(d.Get(utils.TerraformResourceEnv)),
+		Options: v1alpha4.SimpleConfigOptions{
+			K3dOptions: k3dOptions,
+			K3sOptions: flattenK3SOptions(d.Get(utils.TerraformResourceK3sOptions)),
+		},
+	}
+
+	simpleConfig.Options.K3sOptions.ExtraArgs = append(simpleConfig.Options.K3sOptions.ExtraArgs,
+		flattenKubeAPITLSSANs(d.Get(utils.TerraformKubeAPI))...)
+
+	return simpleConfig, nil
+}
@@ -0,0 +1,41 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_getClusterSimpleConfig(t *testing.T) {
+	servers, agents := getImportTestNodes()
+
+	d := schema.TestResourceDataRaw(t, dataSourceClusterConfig().Schema, map[string]any{"name": "test"})
+	attributes := map[string]any{
+		"servers_count": len(servers),
+		"agents_count":  len(agents),
+		"image":         "rancher/k3s:v1.24.4-k3s1",
+		"env":           getClusterEnvVars([]string{"PATH=/bin"}, servers, agents),
+		"k3s_options":   getClusterK3sOptions(servers, agents),
+		"kube_api":      []any{map[string]any{"host": "", "host_ip": "0.0.0.0", "host_port": 6445}},
+		"k3d_options":   []any{map[string]any{"wait": true, "no_loadbalancer": true}},
+	}
+
+	for attribute, value := range attributes {
+		assert.NoError(t, d.Set(attribute, value))
+	}
+
+	simpleConfig, err := getClusterSimpleConfig(d)
+	assert.NoError(t, err)
+	assert.Equal(t, "test", simpleConfig.Name)
+	assert.Equal(t, 2, simpleConfig.Servers)
+	assert.Equal(t, 1, simpleConfig.Agents)
+	assert.Equal(t, "6445", simpleConfig.ExposeAPI.HostPort)
+	assert.True(t, simpleConfig.Options.K3dOptions.DisableLoadbalancer)
+	assert.Len(t, simpleConfig.Env, 1)
+	assert.Equal(t, "LOG=debug", simpleConfig.Env[0].EnvVar)
+	assert.Equal(t, []string{"server:0", "agent:0"}, simpleConfig.Env[0].NodeFilters)
+	assert.Len(t, simpleConfig.Options.K3sOptions.ExtraArgs, 1)
+	assert.Equal(t, "--disable=traefik", simpleConfig.Options.K3sOptions.ExtraArgs[0].Arg)
+	assert.Equal(t, []string{"server:*"}, simpleConfig.Options.K3sOptions.ExtraArgs[0].NodeFilters)
+}
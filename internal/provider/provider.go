@@ -2,6 +2,7 @@ package provider
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/client"
@@ -79,13 +80,15 @@ func Provider() *schema.Provider {
 			"k3d_node":             resourceNode(),
 			"k3d_cluster_action":   resourceClusterAction(),
 			"k3d_cluster":          resourceCluster(),
+			"k3d_network":          resourceNetwork(),
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
-			"k3d_node":       dataSourceNodeList(),
-			"k3d_cluster":    dataSourceClusterList(),
-			"k3d_kubeconfig": dataSourceKubeConfig(),
-			"k3d_registry":   dataSourceRegistryList(),
+			"k3d_node":           dataSourceNodeList(),
+			"k3d_cluster":        dataSourceClusterList(),
+			"k3d_cluster_config": dataSourceClusterConfig(),
+			"k3d_kubeconfig":     dataSourceKubeConfig(),
+			"k3d_registry":       dataSourceRegistryList(),
 		},
 
 		ConfigureContextFunc: client.GetK3dConfig,
@@ -93,7 +96,7 @@ func Provider() *schema.Provider {
 }
 
 func ValidateKindFunc(v any, k string) ([]string, []error) {
-	if v.(string) != "Simple" {
+	if !strings.EqualFold(v.(string), "Simple") {
 		return nil, []error{
 			fmt.Errorf("%w: %s", terraformErrors.ErrUnsupportedKind, k),
 			terraformErrors.ErrConfigFileReference,
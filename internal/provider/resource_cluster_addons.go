@@ -0,0 +1,212 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	terraformErrors "github.com/nikhilsbhat/terraform-provider-k3d/pkg/errors"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/k3d/cluster"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/utils"
+	k3dClient "github.com/rancher/k3d/v5/pkg/client"
+	"github.com/rancher/k3d/v5/pkg/runtimes"
+	K3D "github.com/rancher/k3d/v5/pkg/types"
+)
+
+// customizeClusterAddonsDiff sets the hash of the addons rendered from the config, so that the plan shows the addons
+// that would be rewritten, either because they were changed in config or the files in the server nodes have drifted.
+func customizeClusterAddonsDiff(_ context.Context, d *schema.ResourceDiff, _ any) error {
+	if !d.NewValueKnown(utils.TerraformAddons) {
+		return d.SetNewComputed(utils.TerraformAddonsHash)
+	}
+
+	hashes, err := getAddonsHash(flattenAddons(d.Get(utils.TerraformAddons)))
+	if err != nil {
+		return err
+	}
+
+	if reflect.DeepEqual(flattenAddonsHash(d.Get(utils.TerraformAddonsHash)), hashes) {
+		return nil
+	}
+
+	return d.SetNew(utils.TerraformAddonsHash, hashes)
+}
+
+// writeClusterAddons writes all the addons to the server nodes of the cluster, either newly created or whose servers were replaced or added.
+func writeClusterAddons(ctx context.Context, d *schema.ResourceData, runtime runtimes.Runtime, clusterName string) diag.Diagnostics {
+	addons := flattenAddons(d.Get(utils.TerraformAddons))
+	if len(addons) == 0 {
+		return nil
+	}
+
+	servers, err := getClusterServers(ctx, runtime, clusterName)
+	if err != nil {
+		return diag.Errorf("fetching servers of cluster '%s' errored with: %v", clusterName, err)
+	}
+
+	if err = cluster.WriteAddons(ctx, runtime, servers, addons); err != nil {
+		return diag.Errorf("writing addons to cluster '%s' errored with: %v", clusterName, err)
+	}
+
+	return nil
+}
+
+// updateClusterAddons rewrites the addons whose hash differs from the one in the server nodes and removes the addons dropped from config.
+func updateClusterAddons(ctx context.Context, d *schema.ResourceData, runtime runtimes.Runtime, clusterName string) diag.Diagnostics {
+	oldAddons, newAddons := d.GetChange(utils.TerraformAddons)
+	oldHashes, _ := d.GetChange(utils.TerraformAddonsHash)
+	currentHashes := flattenAddonsHash(oldHashes)
+
+	addons := flattenAddons(newAddons)
+	desired := make(map[string]bool, len(addons))
+	changed := make([]*cluster.Addon, 0)
+
+	for _, addon := range addons {
+		desired[addon.Name] = true
+
+		hash, err := addon.Hash()
+		if err != nil {
+			return diag.Errorf("rendering addon '%s' errored with: %v", addon.Name, err)
+		}
+
+		if currentHashes[addon.Name] != hash {
+			changed = append(changed, addon)
+		}
+	}
+
+	removed := make([]string, 0)
+
+	for _, addon := range flattenAddons(oldAddons) {
+		if !desired[addon.Name] {
+			removed = append(removed, addon.Name)
+		}
+	}
+
+	if len(changed) == 0 && len(removed) == 0 {
+		return nil
+	}
+
+	servers, err := getClusterServers(ctx, runtime, clusterName)
+	if err != nil {
+		return diag.Errorf("fetching servers of cluster '%s' errored with: %v", clusterName, err)
+	}
+
+	if err = cluster.RemoveAddons(ctx, runtime, servers, removed); err != nil {
+		return diag.Errorf("removing addons from cluster '%s' errored with: %v", clusterName, err)
+	}
+
+	if err = cluster.WriteAddons(ctx, runtime, servers, changed); err != nil {
+		return diag.Errorf("writing addons to cluster '%s' errored with: %v", clusterName, err)
+	}
+
+	return nil
+}
+
+// setClusterAddonsHash sets the hash of the addons as found in all the server nodes of the cluster, the addons missing
+// in any of the servers, like the ones added by k3d_node, are left out so that they are rewritten on next apply.
+func setClusterAddonsHash(ctx context.Context, d *schema.ResourceData, k3dCluster *K3D.Cluster) diag.Diagnostics {
+	addons := flattenAddons(d.Get(utils.TerraformAddons))
+	names := make([]string, 0, len(addons))
+
+	for _, addon := range addons {
+		names = append(names, addon.Name)
+	}
+
+	hashes := make(map[string]string)
+
+	if servers := filterServers(k3dCluster.Nodes); len(names) != 0 && len(servers) != 0 {
+		var err error
+		if hashes, err = cluster.GetAddonHashes(ctx, runtimes.SelectedRuntime, servers, names); err != nil {
+			return diag.Errorf("reading addons of cluster '%s' errored with %v", k3dCluster.Name, err)
+		}
+	}
+
+	if err := d.Set(utils.TerraformAddonsHash, hashes); err != nil {
+		return diag.Errorf("setting %s errored with %v", utils.TerraformAddonsHash, err)
+	}
+
+	return nil
+}
+
+func getAddonsHash(addons []*cluster.Addon) (map[string]string, error) {
+	hashes := make(map[string]string, len(addons))
+
+	for _, addon := range addons {
+		if _, ok := hashes[addon.Name]; ok {
+			return nil, fmt.Errorf("%w: name '%s' is used by more than one addon", terraformErrors.ErrInvalidAddon, addon.Name)
+		}
+
+		hash, err := addon.Hash()
+		if err != nil {
+			return nil, err
+		}
+
+		hashes[addon.Name] = hash
+	}
+
+	return hashes, nil
+}
+
+func getClusterServers(ctx context.Context, runtime runtimes.Runtime, clusterName string) ([]*K3D.Node, error) {
+	k3dCluster, err := k3dClient.ClusterGet(ctx, runtime, &K3D.Cluster{Name: clusterName})
+	if err != nil {
+		return nil, err
+	}
+
+	return filterServers(k3dCluster.Nodes), nil
+}
+
+func filterServers(nodes []*K3D.Node) []*K3D.Node {
+	servers := make([]*K3D.Node, 0)
+
+	for _, node := range nodes {
+		if node.Role == K3D.ServerRole {
+			servers = append(servers, node)
+		}
+	}
+
+	return servers
+}
+
+func flattenAddons(addons any) []*cluster.Addon {
+	k3dAddons := make([]*cluster.Addon, 0)
+
+	for _, addon := range addons.([]any) {
+		if addon == nil {
+			continue
+		}
+
+		a := addon.(map[string]any)
+		k3dAddon := &cluster.Addon{
+			Name:     a["name"].(string),
+			Manifest: a["manifest"].(string),
+		}
+
+		if helmChart := a["helm_chart"].([]any); len(helmChart) != 0 && helmChart[0] != nil {
+			h := helmChart[0].(map[string]any)
+			k3dAddon.HelmChart = &cluster.HelmChart{
+				Chart:           h["chart"].(string),
+				Repo:            h["repo"].(string),
+				Version:         h["version"].(string),
+				TargetNamespace: h["target_namespace"].(string),
+				Values:          h["values"].(string),
+			}
+		}
+
+		k3dAddons = append(k3dAddons, k3dAddon)
+	}
+
+	return k3dAddons
+}
+
+func flattenAddonsHash(hashes any) map[string]string {
+	addonsHash := make(map[string]string)
+
+	for name, hash := range hashes.(map[string]any) {
+		addonsHash[name] = hash.(string)
+	}
+
+	return addonsHash
+}
@@ -0,0 +1,106 @@
+package provider
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/k3d/cluster"
+	"github.com/rancher/k3d/v5/pkg/runtimes"
+	runtimeErrors "github.com/rancher/k3d/v5/pkg/runtimes/errors"
+	K3D "github.com/rancher/k3d/v5/pkg/types"
+)
+
+// addonsRuntime returns the files it holds for every node as tar archive, the rest of the runtime is not implemented.
+type addonsRuntime struct {
+	runtimes.Runtime
+	files map[string]map[string]string
+}
+
+func (runtime *addonsRuntime) ReadFromNode(_ context.Context, path string, node *K3D.Node) (io.ReadCloser, error) {
+	content, ok := runtime.files[node.Name][path]
+	if !ok {
+		return nil, runtimeErrors.ErrRuntimeFileNotFound
+	}
+
+	var archive bytes.Buffer
+
+	writer := tar.NewWriter(&archive)
+	if err := writer.WriteHeader(&tar.Header{Name: path, Mode: 0o644, Size: int64(len(content))}); err != nil {
+		return nil, err
+	}
+
+	if _, err := writer.Write([]byte(content)); err != nil {
+		return nil, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return io.NopCloser(&archive), nil
+}
+
+func TestSetClusterAddonsHash(t *testing.T) {
+	selectedRuntime := runtimes.SelectedRuntime
+	defer func() { runtimes.SelectedRuntime = selectedRuntime }()
+
+	manifest := "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: apps\n"
+	k3dCluster := &K3D.Cluster{
+		Name: "test",
+		Nodes: []*K3D.Node{
+			{Name: "k3d-test-server-0", Role: K3D.ServerRole},
+			{Name: "k3d-test-server-1", Role: K3D.ServerRole},
+			{Name: "k3d-test-agent-0", Role: K3D.AgentRole},
+		},
+	}
+
+	newClusterData := func(t *testing.T) *schema.ResourceData {
+		t.Helper()
+
+		return schema.TestResourceDataRaw(t, resourceCluster().Schema, map[string]any{
+			"name": "test",
+			"addons": []any{
+				map[string]any{"name": "apps", "manifest": manifest},
+				map[string]any{"name": "monitoring", "manifest": manifest},
+			},
+		})
+	}
+
+	t.Run("should set the hash of the addons found in all the servers", func(t *testing.T) {
+		runtimes.SelectedRuntime = &addonsRuntime{files: map[string]map[string]string{
+			"k3d-test-server-0": {cluster.AddonFilePath("apps"): manifest, cluster.AddonFilePath("monitoring"): manifest},
+			"k3d-test-server-1": {cluster.AddonFilePath("apps"): manifest, cluster.AddonFilePath("monitoring"): manifest},
+		}}
+
+		d := newClusterData(t)
+		if diags := setClusterAddonsHash(context.Background(), d, k3dCluster); diags.HasError() {
+			t.Fatalf("expected no error, got %v", diags)
+		}
+
+		hash := cluster.HashAddonContent([]byte(manifest))
+		if got, want := flattenAddonsHash(d.Get("addons_hash")), map[string]string{"apps": hash, "monitoring": hash}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("expected addons_hash %v, got %v", want, got)
+		}
+	})
+
+	t.Run("should leave out the addons missing or differing in any of the servers", func(t *testing.T) {
+		runtimes.SelectedRuntime = &addonsRuntime{files: map[string]map[string]string{
+			"k3d-test-server-0": {cluster.AddonFilePath("apps"): manifest, cluster.AddonFilePath("monitoring"): manifest},
+			"k3d-test-server-1": {cluster.AddonFilePath("apps"): "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: edited\n"},
+		}}
+
+		d := newClusterData(t)
+		if diags := setClusterAddonsHash(context.Background(), d, k3dCluster); diags.HasError() {
+			t.Fatalf("expected no error, got %v", diags)
+		}
+
+		if got := flattenAddonsHash(d.Get("addons_hash")); len(got) != 0 {
+			t.Fatalf("expected no addons_hash, got %v", got)
+		}
+	})
+}
@@ -2,23 +2,34 @@ package provider
 
 import (
 	"context"
+	stdErrors "errors"
 	"fmt"
+	"log"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/docker/go-connections/nat"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	k3dCmdUtil "github.com/k3d-io/k3d/v5/cmd/util"
 	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/client"
+	terraformErrors "github.com/nikhilsbhat/terraform-provider-k3d/pkg/errors"
 	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/k3d/cluster"
+	k3dKube "github.com/nikhilsbhat/terraform-provider-k3d/pkg/k3d/config"
+	k3dNode "github.com/nikhilsbhat/terraform-provider-k3d/pkg/k3d/node"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/k3d/registry"
 	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/utils"
 	k3dClient "github.com/rancher/k3d/v5/pkg/client"
 	"github.com/rancher/k3d/v5/pkg/config/types"
 	"github.com/rancher/k3d/v5/pkg/config/v1alpha4"
 	"github.com/rancher/k3d/v5/pkg/runtimes"
 	types2 "github.com/rancher/k3d/v5/pkg/types"
+	"github.com/rancher/k3d/v5/pkg/types/k3s"
+	"github.com/thoas/go-funk"
 	"sigs.k8s.io/yaml"
 )
 
@@ -28,37 +39,59 @@ func resourceCluster() *schema.Resource {
 		CreateContext: resourceClusterCreate,
 		ReadContext:   resourceClusterRead,
 		DeleteContext: resourceClusterDelete,
-		// UpdateContext: resourceClusterUpdate,
+		UpdateContext: resourceClusterUpdate,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceClusterImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(utils.TerraformTimeOut10 * time.Minute),
+			Update: schema.DefaultTimeout(utils.TerraformTimeOut10 * time.Minute),
+			Delete: schema.DefaultTimeout(utils.TerraformTimeOut5 * time.Minute),
+		},
+		CustomizeDiff: customdiff.All(
+			customizeClusterImageDiff,
+			customizeClusterVolumesDiff,
+			customizeClusterConfigDiff,
+			customizeClusterSimpleConfigDiff,
+			customizeClusterRegistriesDiff,
+			customizeClusterAddonsDiff,
+			customizeClusterNodePoolsDiff,
+		),
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
 				Computed:    true,
 				Optional:    true,
+				ForceNew:    true,
 				Description: "Name of the Cluster to be created",
 			},
 			"servers_count": {
-				Type:        schema.TypeInt,
-				Computed:    true,
-				Optional:    true,
-				Description: "Count of servers",
+				Type:     schema.TypeInt,
+				Computed: true,
+				Optional: true,
+				Description: "Count of servers, changing it scales the servers of the cluster in place without breaking etcd quorum, " +
+					"the servers removed are removed from embedded etcd through the kubernetes API before their containers are deleted",
 			},
 			"agents_count": {
 				Type:        schema.TypeInt,
 				Computed:    true,
 				Optional:    true,
-				Description: "Count of agents in the cluster",
+				Description: "Count of agents in the cluster, changing it scales the agents of the cluster in place",
 			},
 			"image": {
 				Type:        schema.TypeString,
 				Optional:    true,
+				Computed:    true,
 				DefaultFunc: schema.EnvDefaultFunc("K3D_IMAGE", nil),
-				ForceNew:    true,
-				Description: "Image name to be used for creation of cluster, it would be used along with kubernetes_version",
+				Description: "Image name to be used for creation of cluster, it would be used along with kubernetes_version. " +
+					"Changing it, or kubernetes_version of the provider when it is not set, upgrades the nodes created along with the cluster " +
+					"one at a time, servers first and then agents",
 			},
 			"network": {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Computed:    true,
+				ForceNew:    true,
 				Description: "Network to be associated with the cluster",
 			},
 			"subnetwork": {
@@ -66,13 +99,14 @@ func resourceCluster() *schema.Resource {
 				Optional:    true,
 				ForceNew:    true,
 				Description: "Define a subnet for the newly created container network",
-				Computed:    false,
+				Computed:    true,
 			},
 			"cluster_token": {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Computed:    true,
 				Sensitive:   true,
+				ForceNew:    true,
 				Description: "superSecretToken to be used",
 			},
 			"volumes": {
@@ -80,7 +114,8 @@ func resourceCluster() *schema.Resource {
 				Optional:    true,
 				Computed:    false,
 				ForceNew:    true,
-				Description: "Mount volumes into the nodes (Format: [SOURCE:]DEST[@NODEFILTER[;NODEFILTER...]]",
+				Description: "Mount volumes into the nodes (Format: [SOURCE:]DEST[:MODE][@NODEFILTER[;NODEFILTER...]]",
+				Set:         resourceClusterVolumeHash,
 				Elem: &schema.Resource{
 					Schema: resourceClusterVolumeSchema(),
 				},
@@ -91,6 +126,7 @@ func resourceCluster() *schema.Resource {
 				Optional: true,
 				Description: "Map ports from the node containers (via the serverlb) to the host " +
 					"(Format: [HOST:][HOSTPORT:]CONTAINERPORT[/PROTOCOL][@NODEFILTER])",
+				Set: resourceClusterPortHash,
 				Elem: &schema.Resource{
 					Schema: resourceClusterPortsConfig(),
 				},
@@ -115,6 +151,44 @@ func resourceCluster() *schema.Resource {
 					Schema: resourceClusterRegistriesSchema(),
 				},
 			},
+			"created_registry": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Details of the registry created along with the cluster, when `registries.create` is enabled",
+				Elem: &schema.Resource{
+					Schema: resourceClusterCreatedRegistrySchema(),
+				},
+			},
+			"nodes": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Details of the nodes of the cluster",
+				Elem: &schema.Resource{
+					Schema: resourceClusterNodesSchema(),
+				},
+			},
+			"host": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Endpoint of the kube API of the cluster",
+			},
+			"cluster_ca_certificate": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "PEM encoded CA certificate of the kube API of the cluster",
+			},
+			"client_certificate": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "PEM encoded client certificate to authenticate with the kube API of the cluster",
+			},
+			"client_key": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "PEM encoded client key to authenticate with the kube API of the cluster",
+			},
 			"host_aliases": {
 				Type:        schema.TypeSet,
 				ForceNew:    true,
@@ -128,31 +202,12 @@ func resourceCluster() *schema.Resource {
 				Description: "same as `--api-port myhost.my.domain:6445` (where the name would resolve to 127.0.0.1)",
 				ForceNew:    true,
 				Optional:    true,
+				Computed:    true,
 				Type:        schema.TypeSet,
 				MaxItems:    1,
+				Set:         resourceClusterKubeAPIHash,
 				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"host": {
-							Description: "Important for the `server` setting in the kubeconfig.",
-							ForceNew:    true,
-							Optional:    true,
-							Type:        schema.TypeString,
-						},
-						"host_ip": {
-							Description:  "Where the Kubernetes API will be listening on.",
-							ForceNew:     true,
-							Optional:     true,
-							Type:         schema.TypeString,
-							ValidateFunc: validation.IsIPAddress,
-						},
-						"host_port": {
-							Description:  "Specify the Kubernetes API server port exposed on the LoadBalancer.",
-							ForceNew:     true,
-							Optional:     true,
-							Type:         schema.TypeInt,
-							ValidateFunc: validation.IsPortNumber,
-						},
-					},
+					Schema: resourceClusterKubeAPISchema(),
 				},
 			},
 			"k3d_options": {
@@ -160,7 +215,7 @@ func resourceCluster() *schema.Resource {
 				Optional:    true,
 				Computed:    false,
 				ForceNew:    true,
-				Description: "k3d runtime settings",
+				Description: "k3d runtime settings, when set they replace the k3d options of simple_config as a whole",
 				Elem: &schema.Resource{
 					Schema: resourceClusterK3dOptionsSchema(),
 				},
@@ -172,32 +227,34 @@ func resourceCluster() *schema.Resource {
 				Computed:    false,
 				Description: "Options passed on to K3s itself",
 				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"extra_args": {
-							Type:        schema.TypeList,
-							Optional:    true,
-							Computed:    false,
-							Description: "additional arguments passed to the `k3s server|agent` command; same as `--k3s-arg`",
-							Elem: &schema.Resource{
-								Schema: resourceClusterEnvsAndLabelsSchema(),
-							},
-						},
-						"node_labels": {
-							Type:        schema.TypeList,
-							Optional:    true,
-							Computed:    false,
-							Description: "same as `--k3s-node-label 'foo=bar@agent:1'` -> this results in a Kubernetes node label",
-							Elem: &schema.Resource{
-								Schema: resourceClusterEnvsAndLabelsSchema(),
-							},
-						},
-					},
+					Schema: resourceClusterK3sOptionsSchema(),
+				},
+			},
+			"k3s": {
+				Type:     schema.TypeList,
+				ForceNew: true,
+				Optional: true,
+				MaxItems: 1,
+				Description: "Components and networking options of K3s, passed on to the servers as k3s arguments. " +
+					"These should not be set again with `k3s_options.extra_args`",
+				Elem: &schema.Resource{
+					Schema: resourceClusterK3sSchema(),
+				},
+			},
+			"node_pool": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Description: "Pools of agents, each with its own settings, created along with the cluster in addition to `agents_count` agents. " +
+					"The agents of a pool do not copy the settings of the other agents, like `k3s_options.extra_args`. " +
+					"Changing the count of a pool scales only that pool, changing any of its other settings recreates the agents of the pool",
+				Elem: &schema.Resource{
+					Schema: resourceClusterNodePoolSchema(),
 				},
 			},
 			"kube_config": {
-				Type:        schema.TypeSet,
-				ForceNew:    true,
+				Type:        schema.TypeList,
 				Optional:    true,
+				MaxItems:    1,
 				Description: "Way to manage the kubeconfig generated after creating k3d clusters.",
 				Computed:    false,
 				Elem: &schema.Resource{
@@ -218,7 +275,46 @@ func resourceCluster() *schema.Resource {
 				Type:        schema.TypeString,
 				Computed:    true,
 				Optional:    true,
-				Description: "",
+				Description: "Not used by the provider, the value set is ignored",
+			},
+			"addons": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Description: "Manifests or HelmCharts written to `" + cluster.AddonsManifestsDir + "` of the server nodes, " +
+					"which k3s applies automatically. Addons are rewritten in place when their content changes or they are missing in any of the server nodes",
+				Elem: &schema.Resource{
+					Schema: resourceClusterAddonsSchema(),
+				},
+			},
+			"addons_hash": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "sha256 of the manifest of every addon written to the server nodes, keyed by the name of the addon",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"wait_for": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Description: "Waits for the cluster to be ready at the kubernetes level after it is created or its nodes are changed, " +
+					"in addition to the containers readiness checked by `k3d_options.wait`. Changing it waits for the cluster again",
+				Elem: &schema.Resource{
+					Schema: resourceClusterWaitForSchema(),
+				},
+			},
+			"simple_config": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Description: "k3d [config](https://k3d.io/v5.4.7/usage/configfile/) of kind `Simple` to create the cluster from, " +
+					"either path to the config file or inline YAML. Attributes set here overrides the matching fields of the config, even when set to false, 0 or empty",
+				ValidateDiagFunc: validateSimpleConfig,
+			},
+			"simple_config_hash": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "sha256 of the contents of simple_config, changes to the contents of the config file " +
+					"recreate the cluster the same way as the changes to simple_config",
 			},
 		},
 	}
@@ -233,64 +329,129 @@ func resourceClusterCreate(ctx context.Context, d *schema.ResourceData, meta any
 
 	id := d.Id()
 
-	if len(id) == 0 {
-		resourceID := utils.String(d.Get(utils.TerraformResourceName))
-		id = resourceID
+	cfg, err := flattenSimpleConfig(d)
+	if err != nil {
+		return diag.Errorf("%v", err)
 	}
 
-	clusterName := utils.String(d.Get(utils.TerraformResourceName))
+	if simpleConfig := utils.String(d.Get(utils.TerraformSimpleConfig)); len(simpleConfig) != 0 {
+		if cfg, err = mergeSimpleConfig(d, cfg); err != nil {
+			return diag.Diagnostics{{
+				Severity:      diag.Error,
+				Summary:       fmt.Sprintf("reading %s errored", utils.TerraformSimpleConfig),
+				Detail:        err.Error(),
+				AttributePath: cty.GetAttrPath(utils.TerraformSimpleConfig),
+			}}
+		}
+	}
 
-	k3dImage := utils.String(d.Get(utils.TerraformResourceImage))
-	if len(k3dImage) == 0 {
-		k3dImage = defaultConfig.GetK3dImage()
+	if len(cfg.Image) == 0 {
+		cfg.Image = defaultConfig.GetK3dImage()
 	}
 
-	cfg := &v1alpha4.SimpleConfig{
-		ObjectMeta: types.ObjectMeta{
-			Name: clusterName,
-		},
-		Servers:      utils.Int(d.Get(utils.TerraformResourceServersCount)),
-		Agents:       utils.Int(d.Get(utils.TerraformResourceAgentsCount)),
-		Image:        k3dImage,
-		Network:      utils.String(d.Get(utils.TerraformResourceNetwork)),
-		Subnet:       utils.String(d.Get(utils.TerraformResourceSubnet)),
-		ClusterToken: utils.String(d.Get(utils.TerraformResourceClusterToken)),
-		ExposeAPI:    flattenKubeAPI(d.Get(utils.TerraformKubeAPI)),
-		Volumes:      flattenVolumes(d.Get(utils.TerraformResourceVolumes)),
-		Ports:        flattenPorts(d.Get(utils.TerraformResourcePorts)),
-		Env:          flattenEnvVars(d.Get(utils.TerraformResourceEnv)),
-		Registries:   flattenRegistries(d.Get(utils.TerraformResourceRegistries)),
-		HostAliases:  flattenHostAlias(d.Get(utils.TerraformHostAlias)),
+	if len(cfg.ExposeAPI.HostPort) == 0 {
+		hostPort, err := k3dCmdUtil.GetFreePort()
+		if err != nil {
+			return diag.Errorf("fetching free port for the kubernetes API errored with: %v", err)
+		}
+
+		cfg.ExposeAPI.HostPort = strconv.Itoa(hostPort)
 	}
 
-	k3dOptions, err := flattenK3DOptions(d.Get(utils.TerraformResourceK3dOptions))
-	if err != nil {
-		return diag.Errorf("fetching %s errored with: %v", utils.TerraformResourceK3dOptions, err)
+	clusterName := cfg.Name
+	if len(id) == 0 {
+		id = clusterName
 	}
 
-	cfg.Options = v1alpha4.SimpleConfigOptions{
-		K3dOptions:        k3dOptions,
-		K3sOptions:        flattenK3SOptions(d.Get(utils.TerraformResourceK3sOptions)),
-		KubeconfigOptions: flattenKubeConfig(d.Get(utils.TerraformResourceKubeConfig)),
-		Runtime:           flattenRuntime(d.Get(utils.TerraformK3dRuntime)),
+	registryCfg := flattenRegistryConfig(d.Get(utils.TerraformResourceRegistries))
+	volumes := flattenClusterVolumes(d.Get(utils.TerraformResourceVolumes))
+
+	if err = cluster.CreateNamedVolumes(ctx, defaultConfig.K3DRuntime, clusterName, volumes); err != nil {
+		return diag.Errorf("creating volumes of cluster '%s' errored with: %v", clusterName, err)
 	}
 
-	if err = cluster.CreateCluster(ctx, defaultConfig.K3DRuntime, cfg); err != nil {
-		if delErr := cluster.CheckAndDeleteCluster(ctx, defaultConfig.K3DRuntime, clusterName); delErr != nil {
+	nodePools := flattenClusterNodePools(d.Get(utils.TerraformResourceNodePool), clusterName, cfg.Image, cfg.Options.K3dOptions)
+
+	err = cluster.CreateCluster(ctx, defaultConfig.K3DRuntime, cfg, registryCfg)
+	if err == nil {
+		err = createClusterNodePools(ctx, defaultConfig.K3DRuntime, nodePools)
+	}
+
+	if err != nil {
+		if cfg.Options.K3dOptions.NoRollback {
+			return diag.Errorf("creating cluster '%s' errored with: %v", clusterName, err)
+		}
+
+		// the rollback has to happen even if the create timeout has been hit.
+		rollbackCtx, cancel := utils.RollbackContext(ctx)
+		defer cancel()
+
+		if delErr := cluster.CheckAndDeleteCluster(rollbackCtx, defaultConfig.K3DRuntime, clusterName); delErr != nil {
 			return diag.Errorf("creation of cluster '%s' FAILED with: %v\n, also FAILED to rollback changes!: %v", clusterName, err, delErr)
 		}
 
+		if delErr := cluster.DeleteNamedVolumes(rollbackCtx, defaultConfig.K3DRuntime, clusterName, volumes); delErr != nil {
+			return diag.Errorf("creation of cluster '%s' FAILED with: %v\n, also FAILED to remove its volumes!: %v", clusterName, err, delErr)
+		}
+
 		return diag.Errorf("creating cluster '%s' errored with: %v", clusterName, err)
 	}
 
 	d.SetId(id)
 
+	kubeConfigOptions := flattenKubeConfigOptions(d.Get(utils.TerraformResourceKubeConfig))
+	kubeConfigOptions.UpdateDefault = cfg.Options.KubeconfigOptions.UpdateDefaultKubeconfig
+	kubeConfigOptions.SwitchContext = cfg.Options.KubeconfigOptions.SwitchCurrentContext
+
+	if err = kubeConfigOptions.Write(ctx, defaultConfig.K3DRuntime, &types2.Cluster{Name: clusterName}); err != nil {
+		return diag.Errorf("writing kubeconfig of cluster '%s' errored with: %v", clusterName, err)
+	}
+
+	if err = d.Set(utils.TerraformResourceName, clusterName); err != nil {
+		return diag.Errorf("setting %s errored with %v", utils.TerraformResourceName, err)
+	}
+
+	if diags := writeClusterAddons(ctx, d, defaultConfig.K3DRuntime, clusterName); diags != nil {
+		return diags
+	}
+
+	if diags := waitForCluster(ctx, d, defaultConfig.K3DRuntime, clusterName, cfg.Servers+cfg.Agents+getClusterNodePoolsCount(nodePools)); diags != nil {
+		return diags
+	}
+
+	if err = d.Set(utils.TerraformResourceImage, cfg.Image); err != nil {
+		return diag.Errorf("setting %s errored with %v", utils.TerraformResourceImage, err)
+	}
+
+	simpleConfigHash, err := getSimpleConfigHash(utils.String(d.Get(utils.TerraformSimpleConfig)))
+	if err != nil {
+		return diag.Errorf("hashing %s errored with: %v", utils.TerraformSimpleConfig, err)
+	}
+
+	if err = d.Set(utils.TerraformSimpleConfigHash, simpleConfigHash); err != nil {
+		return diag.Errorf("setting %s errored with %v", utils.TerraformSimpleConfigHash, err)
+	}
+
 	return resourceClusterRead(ctx, d, meta)
 }
 
 func resourceClusterRead(ctx context.Context, d *schema.ResourceData, _ any) diag.Diagnostics {
 	clusterName := utils.String(d.Get(utils.TerraformResourceName))
 
+	// k3d reports any failure of the runtime as the cluster having no nodes, hence the nodes are looked up first
+	// so that the cluster is removed from state only when the runtime confirms that none of its nodes exist.
+	clusterNodes, err := runtimes.SelectedRuntime.GetNodesByLabel(ctx, map[string]string{types2.LabelClusterName: clusterName})
+	if err != nil {
+		return diag.Errorf("fetching nodes of cluster '%s' errored with %v", clusterName, err)
+	}
+
+	if len(clusterNodes) == 0 {
+		log.Printf("cluster '%s' not found, removing it from state", clusterName)
+		d.SetId("")
+
+		return nil
+	}
+
 	k3dCluster, err := k3dClient.ClusterGet(ctx, runtimes.SelectedRuntime, &types2.Cluster{Name: clusterName})
 	if err != nil {
 		return diag.Errorf("fetching cluster '%s' errored with %v", clusterName, err)
@@ -304,6 +465,64 @@ func resourceClusterRead(ctx context.Context, d *schema.ResourceData, _ any) dia
 		return diag.Errorf("setting %s errored with %v", utils.TerraformResourceClusterToken, err)
 	}
 
+	if err = d.Set(utils.TerraformResourceServersCount, getClusterNodesCount(k3dCluster, types2.ServerRole)); err != nil {
+		return diag.Errorf("setting %s errored with %v", utils.TerraformResourceServersCount, err)
+	}
+
+	if err = d.Set(utils.TerraformResourceAgentsCount, getClusterNodesCount(k3dCluster, types2.AgentRole)); err != nil {
+		return diag.Errorf("setting %s errored with %v", utils.TerraformResourceAgentsCount, err)
+	}
+
+	if err = d.Set(utils.TerraformResourceNodePool, getClusterNodePools(k3dCluster, d.Get(utils.TerraformResourceNodePool).([]any))); err != nil {
+		return diag.Errorf("setting %s errored with %v", utils.TerraformResourceNodePool, err)
+	}
+
+	createdRegistry := getClusterCreatedRegistry(k3dCluster, getClusterRegistryName(clusterName, d.Get(utils.TerraformResourceRegistries)))
+	if err = d.Set(utils.TerraformResourceCreatedRegistry, createdRegistry); err != nil {
+		return diag.Errorf("setting %s errored with %v", utils.TerraformResourceCreatedRegistry, err)
+	}
+
+	inspector, err := k3dNode.NewInspector(runtimes.SelectedRuntime)
+	if err != nil {
+		return diag.Errorf("inspecting nodes of cluster '%s' errored with %v", clusterName, err)
+	}
+
+	defer inspector.Close()
+
+	if err = d.Set(utils.TerraformResourceNodes, getClusterNodes(ctx, inspector, k3dCluster)); err != nil {
+		return diag.Errorf("setting %s errored with %v", utils.TerraformResourceNodes, err)
+	}
+
+	kubeAPI, err := getClusterPublishedKubeAPI(ctx, inspector, k3dCluster, d.Get(utils.TerraformKubeAPI).(*schema.Set).List())
+	if err != nil {
+		return diag.Errorf("reading published port of the kubernetes API of cluster '%s' errored with %v", clusterName, err)
+	}
+
+	if err = d.Set(utils.TerraformKubeAPI, kubeAPI); err != nil {
+		return diag.Errorf("setting %s errored with %v", utils.TerraformKubeAPI, err)
+	}
+
+	ports, err := getClusterPublishedPorts(ctx, inspector, k3dCluster, d.Get(utils.TerraformResourcePorts).(*schema.Set).List())
+	if err != nil {
+		return diag.Errorf("reading published ports of cluster '%s' errored with %v", clusterName, err)
+	}
+
+	if err = d.Set(utils.TerraformResourcePorts, ports); err != nil {
+		return diag.Errorf("setting %s errored with %v", utils.TerraformResourcePorts, err)
+	}
+
+	if diags := setClusterCredentials(ctx, d, k3dCluster); diags != nil {
+		return diags
+	}
+
+	if diags := checkClusterKubeConfigDrift(ctx, d, k3dCluster); diags != nil {
+		return diags
+	}
+
+	if diags := setClusterAddonsHash(ctx, d, k3dCluster); diags != nil {
+		return diags
+	}
+
 	yamlOUT, err := yaml.Marshal(k3dCluster)
 	if err != nil {
 		return diag.Errorf("marshalling to yaml errored with: %v", err)
@@ -316,138 +535,554 @@ func resourceClusterRead(ctx context.Context, d *schema.ResourceData, _ any) dia
 	return nil
 }
 
-func resourceClusterDelete(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	defaultConfig := meta.(*client.Config)
+func getClusterNodes(ctx context.Context, inspector *k3dNode.Inspector, k3dCluster *types2.Cluster) []map[string]any {
+	nodes := make([]map[string]any, 0, len(k3dCluster.Nodes))
 
-	id := d.Id()
-	if len(id) == 0 {
-		return diag.Errorf("resource with the specified ID not found")
-	}
+	for _, node := range k3dCluster.Nodes {
+		// k3d reports the ID of the image, the reference is fetched from the runtime and the ID is used only when that fails.
+		image := node.Image
+		if nodeImage, err := inspector.GetNodeImage(ctx, node.Name); err != nil {
+			log.Printf("fetching image of node '%s' errored with %v, using image ID instead", node.Name, err)
+		} else {
+			image = nodeImage.Reference
+		}
 
-	clusterName := utils.String(d.Get(utils.TerraformResourceName))
-	if err := cluster.CheckAndDeleteCluster(ctx, defaultConfig.K3DRuntime, clusterName); err != nil {
-		return diag.Errorf("deleting cluster '%s' errored with %v", clusterName, err)
+		nodes = append(nodes, getClusterNodeDetails(node, image))
 	}
 
-	return nil
+	return nodes
 }
 
-func flattenPorts(ports any) []v1alpha4.PortWithNodeFilters {
-	k3dPorts := make([]v1alpha4.PortWithNodeFilters, 0)
-
-	for _, port := range ports.(*schema.Set).List() {
-		p := port.(map[string]any)
-		k3dPorts = append(k3dPorts, v1alpha4.PortWithNodeFilters{
-			Port:        getPortMappings(p),
-			NodeFilters: utils.GetSlice(p["node_filters"].([]any)),
-		})
+func getClusterNodeDetails(node *types2.Node, image string) map[string]any {
+	ip := ""
+	if !node.IP.IP.IsZero() {
+		ip = node.IP.IP.String()
 	}
 
-	return k3dPorts
+	return map[string]any{
+		"name":   node.Name,
+		"role":   string(node.Role),
+		"ip":     ip,
+		"state":  node.State.Status,
+		"image":  image,
+		"memory": node.Memory,
+		"ports":  getNodePorts(node.Ports),
+	}
 }
 
-func getPortMappings(p map[string]any) string {
-	return fmt.Sprintf("%s:%d:%d/%s", p["host"].(string), p["host_port"].(int), p["container_port"].(int), p["protocol"].(string))
-}
+func getNodePorts(portMap nat.PortMap) []map[string]any {
+	containerPorts := make([]string, 0, len(portMap))
+	for containerPort := range portMap {
+		containerPorts = append(containerPorts, string(containerPort))
+	}
 
-func flattenVolumes(volumes any) []v1alpha4.VolumeWithNodeFilters {
-	k3dVolumes := make([]v1alpha4.VolumeWithNodeFilters, 0)
+	sort.Strings(containerPorts)
 
-	for _, volume := range volumes.(*schema.Set).List() {
-		v := volume.(map[string]any)
-		k3dVolumes = append(k3dVolumes, v1alpha4.VolumeWithNodeFilters{
-			Volume:      fmt.Sprintf("%s/%s", v["source"].(string), v["destination"].(string)),
-			NodeFilters: utils.GetSlice(v["node_filters"].([]any)),
-		})
+	ports := make([]map[string]any, 0)
+
+	for _, containerPort := range containerPorts {
+		for _, binding := range portMap[nat.Port(containerPort)] {
+			ports = append(ports, map[string]any{
+				"container_port": containerPort,
+				"host_ip":        binding.HostIP,
+				"host_port":      binding.HostPort,
+			})
+		}
 	}
 
-	return k3dVolumes
+	return ports
 }
 
-func flattenHostAlias(alias any) []types2.HostAlias {
-	k3dAlias := make([]types2.HostAlias, 0)
+// getClusterPublishedKubeAPI sets the host port the kubernetes API is actually published to, by the loadbalancer or by the
+// first server when the cluster has no loadbalancer. The port k3d was asked to publish it to is used when it is not published
+// at the moment, for instance when the cluster is stopped.
+func getClusterPublishedKubeAPI(ctx context.Context, inspector *k3dNode.Inspector, k3dCluster *types2.Cluster, kubeAPI []any) ([]any, error) {
+	servers := getClusterNodesByRole(k3dCluster, types2.ServerRole)
+	if len(servers) == 0 {
+		return kubeAPI, nil
+	}
 
-	for _, port := range alias.(*schema.Set).List() {
-		a := port.(map[string]any)
-		k3dAlias = append(k3dAlias, types2.HostAlias{
-			IP:        a["ip"].(string),
-			Hostnames: utils.GetSlice(a["hostnames"].([]any)),
-		})
+	if len(kubeAPI) == 0 {
+		kubeAPI = getClusterKubeAPI(servers[0])
 	}
 
-	return k3dAlias
-}
+	if len(kubeAPI) == 0 {
+		return kubeAPI, nil
+	}
 
-func flattenEnvVars(envs any) []v1alpha4.EnvVarWithNodeFilters {
-	k3dEnvs := make([]v1alpha4.EnvVarWithNodeFilters, 0)
+	apiNode := servers[0]
+	for _, node := range k3dCluster.Nodes {
+		if node.Role == types2.LoadBalancerRole {
+			apiNode = node
+		}
+	}
 
-	for _, port := range envs.(*schema.Set).List() {
-		e := port.(map[string]any)
-		k3dEnvs = append(k3dEnvs, v1alpha4.EnvVarWithNodeFilters{
-			EnvVar:      fmt.Sprintf("%s=%s", e["key"].(string), e["value"].(string)),
-			NodeFilters: utils.GetSlice(e["node_filters"].([]any)),
-		})
+	hostPort := 0
+	if servers[0].ServerOpts.KubeAPI != nil {
+		hostPort, _ = strconv.Atoi(servers[0].ServerOpts.KubeAPI.Binding.HostPort)
 	}
 
-	return k3dEnvs
-}
+	publishedPorts, err := inspector.GetNodePublishedPorts(ctx, apiNode.Name)
+	if err != nil {
+		log.Printf("fetching published ports of node '%s' errored with %v, using the port of the kubernetes API from its labels", apiNode.Name, err)
+	}
 
-func flattenKubeAPI(api any) v1alpha4.SimpleExposureOpts {
-	var exposureOpts v1alpha4.SimpleExposureOpts
+	for _, binding := range publishedPorts[nat.Port(fmt.Sprintf("%s/tcp", types2.DefaultAPIPort))] {
+		if port, err := strconv.Atoi(binding.HostPort); err == nil {
+			hostPort = port
 
-	if api.(*schema.Set).Len() == 0 {
-		return exposureOpts
+			break
+		}
 	}
 
-	apiList := api.(*schema.Set).List()
-	a := apiList[0].(map[string]any)
+	if hostPort != 0 {
+		kubeAPI[0].(map[string]any)["host_port"] = hostPort
+	}
 
-	hostPort, _ := k3dCmdUtil.GetFreePort()
+	return kubeAPI, nil
+}
 
-	if a["host_port"].(int) == 0 {
-		exposureOpts.HostPort = strconv.Itoa(hostPort)
-	} else {
-		exposureOpts.HostPort = strconv.Itoa(a["host_port"].(int))
+func setClusterCredentials(ctx context.Context, d *schema.ResourceData, k3dCluster *types2.Cluster) diag.Diagnostics {
+	credentials, err := k3dKube.GetCredentials(ctx, runtimes.SelectedRuntime, k3dCluster)
+	if err != nil {
+		return diag.Errorf("fetching kubeconfig of cluster '%s' errored with %v", k3dCluster.Name, err)
 	}
 
-	exposureOpts.Host = a["host"].(string)
-	exposureOpts.HostIP = a["host_ip"].(string)
+	for key, value := range map[string]string{
+		utils.TerraformResourceClusterHost:   credentials.Host,
+		utils.TerraformResourceClusterCACert: credentials.ClusterCACertificate,
+		utils.TerraformResourceClientCert:    credentials.ClientCertificate,
+		utils.TerraformResourceClientKey:     credentials.ClientKey,
+	} {
+		if err = d.Set(key, value); err != nil {
+			return diag.Errorf("setting %s errored with %v", key, err)
+		}
+	}
 
-	return exposureOpts
+	return nil
 }
 
-func flattenK3DOptions(k3d any) (v1alpha4.SimpleConfigOptionsK3d, error) {
-	k3dList := k3d.(*schema.Set).List()
-
-	if len(k3dList) == 0 {
-		return defaultK3DOptions(), nil
+// waitForCluster waits for the nodes and workloads set in wait_for to be ready, using the kubeconfig of the cluster.
+func waitForCluster(ctx context.Context, d *schema.ResourceData, runtime runtimes.Runtime, clusterName string, nodes int) diag.Diagnostics {
+	readinessCfg, err := flattenWaitFor(d.Get(utils.TerraformWaitFor), nodes)
+	if err != nil {
+		return diag.Errorf("reading %s errored with: %v", utils.TerraformWaitFor, err)
 	}
 
-	k := k3dList[0].(map[string]any)
+	if readinessCfg == nil {
+		return nil
+	}
 
-	k3DOptions := v1alpha4.SimpleConfigOptionsK3d{
-		Wait:                utils.Bool(k["wait"]),
-		DisableLoadbalancer: utils.Bool(k["no_loadbalancer"]),
-		DisableImageVolume:  utils.Bool(k["no_image_volume"]),
-		NoRollback:          utils.Bool(k["no_rollback"]),
-		Loadbalancer: v1alpha4.SimpleConfigOptionsK3dLoadbalancer{
-			ConfigOverrides: utils.GetSlice(k["loadbalancer_config_overrides"].([]any)),
-		},
+	kubeConfig, err := k3dClient.KubeconfigGet(ctx, runtime, &types2.Cluster{Name: clusterName})
+	if err != nil {
+		return diag.Errorf("fetching kubeconfig of cluster '%s' errored with: %v", clusterName, err)
 	}
 
-	if len(k["timeout"].(string)) != 0 {
-		timeout, err := time.ParseDuration(k["timeout"].(string))
-		if err != nil {
-			return k3DOptions, err
-		}
+	kubeClient, err := cluster.NewKubeClient(kubeConfig)
+	if err != nil {
+		return diag.Errorf("creating kubernetes client for cluster '%s' errored with: %v", clusterName, err)
+	}
 
-		k3DOptions.Timeout = timeout
+	if err = readinessCfg.Wait(ctx, kubeClient); err != nil {
+		return diag.Errorf("waiting for cluster '%s' errored with: %v", clusterName, err)
 	}
 
-	return k3DOptions, nil
+	return nil
 }
 
-func defaultK3DOptions() v1alpha4.SimpleConfigOptionsK3d {
+func flattenWaitFor(waitFor any, nodes int) (*cluster.ReadinessConfig, error) {
+	waitForList := waitFor.([]any)
+	if len(waitForList) == 0 || waitForList[0] == nil {
+		return nil, nil //nolint:nilnil
+	}
+
+	w := waitForList[0].(map[string]any)
+
+	interval, err := time.ParseDuration(w["interval"].(string))
+	if err != nil {
+		return nil, err
+	}
+
+	readinessCfg := &cluster.ReadinessConfig{Interval: interval}
+
+	if w["nodes_ready"].(bool) {
+		if readinessCfg.NodesTimeout, err = time.ParseDuration(w["nodes_timeout"].(string)); err != nil {
+			return nil, err
+		}
+
+		readinessCfg.Nodes = nodes
+	}
+
+	deployments, err := flattenWaitForWorkloads(cluster.WorkloadDeployment, w["deployment"].([]any))
+	if err != nil {
+		return nil, err
+	}
+
+	daemonSets, err := flattenWaitForWorkloads(cluster.WorkloadDaemonSet, w["daemonset"].([]any))
+	if err != nil {
+		return nil, err
+	}
+
+	readinessCfg.Workloads = append(deployments, daemonSets...)
+
+	return readinessCfg, nil
+}
+
+func flattenWaitForWorkloads(kind string, workloads []any) ([]cluster.Workload, error) {
+	k3dWorkloads := make([]cluster.Workload, 0, len(workloads))
+
+	for _, workload := range workloads {
+		w := workload.(map[string]any)
+
+		timeout, err := time.ParseDuration(w["timeout"].(string))
+		if err != nil {
+			return nil, err
+		}
+
+		k3dWorkloads = append(k3dWorkloads, cluster.Workload{
+			Kind:      kind,
+			Namespace: w["namespace"].(string),
+			Name:      w["name"].(string),
+			Timeout:   timeout,
+		})
+	}
+
+	return k3dWorkloads, nil
+}
+
+// checkClusterKubeConfigDrift unsets output_path of kube_config in the state when the kubeconfig file written earlier has drifted,
+// so that the next plan rewrites the file.
+func checkClusterKubeConfigDrift(ctx context.Context, d *schema.ResourceData, k3dCluster *types2.Cluster) diag.Diagnostics {
+	kubeConfig := d.Get(utils.TerraformResourceKubeConfig).([]any)
+	if len(kubeConfig) == 0 || kubeConfig[0] == nil {
+		return nil
+	}
+
+	inSync, err := flattenKubeConfigOptions(kubeConfig).InSync(ctx, runtimes.SelectedRuntime, k3dCluster)
+	if err != nil {
+		return diag.Errorf("checking kubeconfig of cluster '%s' for drift errored with %v", k3dCluster.Name, err)
+	}
+
+	if inSync {
+		return nil
+	}
+
+	log.Printf("kubeconfig file of cluster '%s' has drifted, it would be rewritten", k3dCluster.Name)
+
+	k := kubeConfig[0].(map[string]any)
+	k["output_path"] = ""
+
+	if err = d.Set(utils.TerraformResourceKubeConfig, []any{k}); err != nil {
+		return diag.Errorf("setting %s errored with %v", utils.TerraformResourceKubeConfig, err)
+	}
+
+	return nil
+}
+
+func resourceClusterDelete(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	defaultConfig := meta.(*client.Config)
+
+	id := d.Id()
+	if len(id) == 0 {
+		return diag.Errorf("resource with the specified ID not found")
+	}
+
+	clusterName := utils.String(d.Get(utils.TerraformResourceName))
+	if err := cluster.CheckAndDeleteCluster(ctx, defaultConfig.K3DRuntime, clusterName); err != nil {
+		return diag.Errorf("deleting cluster '%s' errored with %v", clusterName, err)
+	}
+
+	volumes := flattenClusterVolumes(d.Get(utils.TerraformResourceVolumes))
+	if err := cluster.DeleteNamedVolumes(ctx, defaultConfig.K3DRuntime, clusterName, volumes); err != nil {
+		return diag.Errorf("deleting volumes of cluster '%s' errored with %v", clusterName, err)
+	}
+
+	kubeConfigOptions := getClusterKubeConfigOptions(d, d.Get(utils.TerraformResourceKubeConfig))
+	if err := kubeConfigOptions.Remove(ctx, &types2.Cluster{Name: clusterName}); err != nil {
+		return diag.Errorf("removing kubeconfig of cluster '%s' errored with %v", clusterName, err)
+	}
+
+	return nil
+}
+
+// getClusterKubeConfigOptions returns the kubeconfig options from kube_config, considering the ones set in simple_config as well.
+func getClusterKubeConfigOptions(d *schema.ResourceData, kubeConfig any) *k3dKube.KubeConfigOptions {
+	kubeConfigOptions := flattenKubeConfigOptions(kubeConfig)
+
+	simpleConfig := utils.String(d.Get(utils.TerraformSimpleConfig))
+	if len(simpleConfig) == 0 {
+		return kubeConfigOptions
+	}
+
+	cfg, err := cluster.LoadSimpleConfig(simpleConfig)
+	if err != nil {
+		log.Printf("reading %s errored with %v, considering only kube_config", utils.TerraformSimpleConfig, err)
+
+		return kubeConfigOptions
+	}
+
+	kubeConfigOptions.UpdateDefault = kubeConfigOptions.UpdateDefault || cfg.Options.KubeconfigOptions.UpdateDefaultKubeconfig
+	kubeConfigOptions.SwitchContext = kubeConfigOptions.SwitchContext || cfg.Options.KubeconfigOptions.SwitchCurrentContext
+
+	return kubeConfigOptions
+}
+
+// attributeGetter is implemented by both schema.ResourceData and schema.ResourceDiff,
+// so that the cluster config is built the same way while creating the cluster and while planning it.
+type attributeGetter interface {
+	Get(key string) any
+	GetRawConfig() cty.Value
+}
+
+// flattenSimpleConfig builds the k3d SimpleConfig from the attributes of the cluster, config from simple_config is not merged here.
+func flattenSimpleConfig(d attributeGetter) (*v1alpha4.SimpleConfig, error) {
+	cfg := &v1alpha4.SimpleConfig{
+		ObjectMeta: types.ObjectMeta{
+			Name: utils.String(d.Get(utils.TerraformResourceName)),
+		},
+		Servers:      utils.Int(d.Get(utils.TerraformResourceServersCount)),
+		Agents:       utils.Int(d.Get(utils.TerraformResourceAgentsCount)),
+		Image:        utils.String(d.Get(utils.TerraformResourceImage)),
+		Network:      utils.String(d.Get(utils.TerraformResourceNetwork)),
+		Subnet:       utils.String(d.Get(utils.TerraformResourceSubnet)),
+		ClusterToken: utils.String(d.Get(utils.TerraformResourceClusterToken)),
+		ExposeAPI:    flattenKubeAPI(d.Get(utils.TerraformKubeAPI)),
+		Volumes:      flattenVolumes(d.Get(utils.TerraformResourceVolumes)),
+		Ports:        flattenPorts(d.Get(utils.TerraformResourcePorts)),
+		Env:          flattenEnvVars(d.Get(utils.TerraformResourceEnv)),
+		HostAliases:  flattenHostAlias(d.Get(utils.TerraformHostAlias)),
+	}
+
+	registries, err := flattenRegistries(d.Get(utils.TerraformResourceRegistries))
+	if err != nil {
+		return nil, fmt.Errorf("rendering %s errored with: %w", utils.TerraformResourceRegistries, err)
+	}
+
+	cfg.Registries = registries
+
+	k3dOptions, err := flattenK3DOptions(d.Get(utils.TerraformResourceK3dOptions))
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s errored with: %w", utils.TerraformResourceK3dOptions, err)
+	}
+
+	// the defaults of k3d_options would override the k3d options of simple_config, hence they are left out when the block is not set.
+	if !hasK3DOptions(d) && len(utils.String(d.Get(utils.TerraformSimpleConfig))) != 0 {
+		k3dOptions = v1alpha4.SimpleConfigOptionsK3d{}
+	}
+
+	cfg.Options = v1alpha4.SimpleConfigOptions{
+		K3dOptions:        k3dOptions,
+		K3sOptions:        flattenK3SOptions(d.Get(utils.TerraformResourceK3sOptions)),
+		KubeconfigOptions: flattenKubeConfig(d.Get(utils.TerraformResourceKubeConfig)),
+		Runtime:           flattenRuntime(d.Get(utils.TerraformK3dRuntime)),
+	}
+
+	k3sConfig := flattenK3s(d.Get(utils.TerraformResourceK3s))
+	if err = k3sConfig.ValidateExtraArgs(cfg.Options.K3sOptions.ExtraArgs); err != nil {
+		return nil, fmt.Errorf("%s: %w", utils.TerraformResourceK3s, err)
+	}
+
+	cfg.Options.K3sOptions.ExtraArgs = append(cfg.Options.K3sOptions.ExtraArgs, flattenKubeAPITLSSANs(d.Get(utils.TerraformKubeAPI))...)
+	cfg.Options.K3sOptions.ExtraArgs = append(cfg.Options.K3sOptions.ExtraArgs, k3sConfig.GetK3sArgs()...)
+
+	return cfg, nil
+}
+
+func flattenPorts(ports any) []v1alpha4.PortWithNodeFilters {
+	k3dPorts := make([]v1alpha4.PortWithNodeFilters, 0)
+
+	for _, port := range ports.(*schema.Set).List() {
+		k3dPorts = append(k3dPorts, flattenClusterPort(port).GetPortWithNodeFilters())
+	}
+
+	return k3dPorts
+}
+
+func flattenClusterPort(port any) *cluster.Port {
+	p := port.(map[string]any)
+
+	hostPort := p["host_port"].(string)
+	// host_port was set to 0 earlier to let the runtime pick the port, which is now done by leaving it empty.
+	if hostPort == "0" {
+		hostPort = ""
+	}
+
+	return &cluster.Port{
+		Host:          p["host"].(string),
+		HostPort:      hostPort,
+		ContainerPort: p["container_port"].(string),
+		Protocol:      p["protocol"].(string),
+		NodeFilters:   utils.GetSlice(p["node_filters"].([]any)),
+	}
+}
+
+// getClusterPublishedPorts sets the host ports the ports are actually published to, the ones known earlier are retained
+// when the ports are not published at the moment, for instance when the cluster is stopped.
+func getClusterPublishedPorts(ctx context.Context, inspector *k3dNode.Inspector, k3dCluster *types2.Cluster, ports []any) ([]any, error) {
+	publishedPorts := make(nat.PortMap)
+
+	for _, node := range k3dCluster.Nodes {
+		nodePorts, err := inspector.GetNodePublishedPorts(ctx, node.Name)
+		if err != nil {
+			log.Printf("fetching published ports of node '%s' errored with %v, retaining the known ports", node.Name, err)
+
+			continue
+		}
+
+		for containerPort, bindings := range nodePorts {
+			publishedPorts[containerPort] = append(publishedPorts[containerPort], bindings...)
+		}
+	}
+
+	for _, port := range ports {
+		p := port.(map[string]any)
+
+		hostPorts, err := flattenClusterPort(p).GetPublishedHostPorts(publishedPorts)
+		if err != nil {
+			return nil, err
+		}
+
+		if funk.ContainsInt(hostPorts, 0) {
+			continue
+		}
+
+		p["published_host_ports"] = hostPorts
+	}
+
+	return ports, nil
+}
+
+func flattenVolumes(volumes any) []v1alpha4.VolumeWithNodeFilters {
+	k3dVolumes := make([]v1alpha4.VolumeWithNodeFilters, 0)
+
+	for _, volume := range flattenClusterVolumes(volumes) {
+		k3dVolumes = append(k3dVolumes, volume.GetVolumeWithNodeFilters())
+	}
+
+	return k3dVolumes
+}
+
+func flattenClusterVolumes(volumes any) []*cluster.Volume {
+	clusterVolumes := make([]*cluster.Volume, 0)
+
+	for _, volume := range volumes.(*schema.Set).List() {
+		v := volume.(map[string]any)
+		clusterVolumes = append(clusterVolumes, &cluster.Volume{
+			// source was documented to be suffixed with a colon earlier, which is now added while building the volume.
+			Source:      strings.TrimSuffix(v["source"].(string), ":"),
+			Destination: v["destination"].(string),
+			Mode:        v["mode"].(string),
+			Type:        v["type"].(string),
+			Retain:      v["retain"].(bool),
+			NodeFilters: utils.GetSlice(v["node_filters"].([]any)),
+		})
+	}
+
+	return clusterVolumes
+}
+
+func flattenHostAlias(alias any) []types2.HostAlias {
+	k3dAlias := make([]types2.HostAlias, 0)
+
+	for _, port := range alias.(*schema.Set).List() {
+		a := port.(map[string]any)
+		k3dAlias = append(k3dAlias, types2.HostAlias{
+			IP:        a["ip"].(string),
+			Hostnames: utils.GetSlice(a["hostnames"].([]any)),
+		})
+	}
+
+	return k3dAlias
+}
+
+func flattenEnvVars(envs any) []v1alpha4.EnvVarWithNodeFilters {
+	k3dEnvs := make([]v1alpha4.EnvVarWithNodeFilters, 0)
+
+	for _, port := range envs.(*schema.Set).List() {
+		e := port.(map[string]any)
+		k3dEnvs = append(k3dEnvs, v1alpha4.EnvVarWithNodeFilters{
+			EnvVar:      fmt.Sprintf("%s=%s", e["key"].(string), e["value"].(string)),
+			NodeFilters: utils.GetSlice(e["node_filters"].([]any)),
+		})
+	}
+
+	return k3dEnvs
+}
+
+func flattenKubeAPI(api any) v1alpha4.SimpleExposureOpts {
+	var exposureOpts v1alpha4.SimpleExposureOpts
+
+	if api.(*schema.Set).Len() == 0 {
+		return exposureOpts
+	}
+
+	apiList := api.(*schema.Set).List()
+	a := apiList[0].(map[string]any)
+
+	// free port is picked only while creating the cluster, so that it is not changed on every plan.
+	if a["host_port"].(int) != 0 {
+		exposureOpts.HostPort = strconv.Itoa(a["host_port"].(int))
+	}
+
+	exposureOpts.Host = a["host"].(string)
+	exposureOpts.HostIP = a["host_ip"].(string)
+
+	return exposureOpts
+}
+
+// flattenKubeAPITLSSANs returns the tls_san of kube_api as the k3s arguments of the servers.
+func flattenKubeAPITLSSANs(api any) []v1alpha4.K3sArgWithNodeFilters {
+	extraArgs := make([]v1alpha4.K3sArgWithNodeFilters, 0)
+
+	for _, a := range api.(*schema.Set).List() {
+		for _, san := range utils.GetSlice(a.(map[string]any)["tls_san"].([]any)) {
+			extraArgs = append(extraArgs, v1alpha4.K3sArgWithNodeFilters{
+				Arg:         fmt.Sprintf("%s=%s", k3dNode.K3sTLSSANArg, san),
+				NodeFilters: []string{"server:*"},
+			})
+		}
+	}
+
+	return extraArgs
+}
+
+func flattenK3DOptions(k3d any) (v1alpha4.SimpleConfigOptionsK3d, error) {
+	k3dList := k3d.(*schema.Set).List()
+
+	if len(k3dList) == 0 {
+		return defaultK3DOptions(), nil
+	}
+
+	k := k3dList[0].(map[string]any)
+
+	k3DOptions := v1alpha4.SimpleConfigOptionsK3d{
+		Wait:                utils.Bool(k["wait"]),
+		DisableLoadbalancer: utils.Bool(k["no_loadbalancer"]),
+		DisableImageVolume:  utils.Bool(k["no_image_volume"]),
+		NoRollback:          utils.Bool(k["no_rollback"]),
+		Loadbalancer: v1alpha4.SimpleConfigOptionsK3dLoadbalancer{
+			ConfigOverrides: utils.GetSlice(k["loadbalancer_config_overrides"].([]any)),
+		},
+	}
+
+	if len(k["timeout"].(string)) != 0 {
+		timeout, err := time.ParseDuration(k["timeout"].(string))
+		if err != nil {
+			return k3DOptions, err
+		}
+
+		k3DOptions.Timeout = timeout
+	}
+
+	return k3DOptions, nil
+}
+
+// hasK3DOptions reports whether k3d_options is set in the config of the cluster.
+func hasK3DOptions(d attributeGetter) bool {
+	return len(d.Get(utils.TerraformResourceK3dOptions).(*schema.Set).List()) != 0
+}
+
+func defaultK3DOptions() v1alpha4.SimpleConfigOptionsK3d {
 	return v1alpha4.SimpleConfigOptionsK3d{
 		Wait:                true,
 		DisableLoadbalancer: false,
@@ -486,6 +1121,25 @@ func flattenExtraArgs(extraArgs []any) []v1alpha4.K3sArgWithNodeFilters {
 	return k3sExtraArgs
 }
 
+func flattenK3s(k3s any) *cluster.K3sConfig {
+	k3sConfig := &cluster.K3sConfig{}
+
+	k3sList := k3s.([]any)
+	if len(k3sList) == 0 || k3sList[0] == nil {
+		return k3sConfig
+	}
+
+	k := k3sList[0].(map[string]any)
+	k3sConfig.Disable = utils.GetSlice(k["disable"].(*schema.Set).List())
+	k3sConfig.FlannelBackend = k["flannel_backend"].(string)
+	k3sConfig.ClusterCIDR = k["cluster_cidr"].(string)
+	k3sConfig.ServiceCIDR = k["service_cidr"].(string)
+	k3sConfig.ClusterDNS = k["cluster_dns"].(string)
+	k3sConfig.ClusterDomain = k["cluster_domain"].(string)
+
+	return k3sConfig
+}
+
 func normalizeK3SArgKey(key string) string {
 	return strings.TrimLeft(key, "-")
 }
@@ -507,11 +1161,11 @@ func flattenNodeLabels(nodeLabels []any) []v1alpha4.LabelWithNodeFilters {
 func flattenKubeConfig(cfg any) v1alpha4.SimpleConfigOptionsKubeconfig {
 	var kubeConfig v1alpha4.SimpleConfigOptionsKubeconfig
 
-	if cfg.(*schema.Set).Len() == 0 {
+	cfgList := cfg.([]any)
+	if len(cfgList) == 0 || cfgList[0] == nil {
 		return kubeConfig
 	}
 
-	cfgList := cfg.(*schema.Set).List()
 	c := cfgList[0].(map[string]any)
 
 	kubeConfig.SwitchCurrentContext = c["switch_context"].(bool)
@@ -520,6 +1174,23 @@ func flattenKubeConfig(cfg any) v1alpha4.SimpleConfigOptionsKubeconfig {
 	return kubeConfig
 }
 
+func flattenKubeConfigOptions(cfg any) *k3dKube.KubeConfigOptions {
+	cfgList := cfg.([]any)
+	if len(cfgList) == 0 || cfgList[0] == nil {
+		return &k3dKube.KubeConfigOptions{}
+	}
+
+	c := cfgList[0].(map[string]any)
+
+	return &k3dKube.KubeConfigOptions{
+		UpdateDefault:  c["update_default"].(bool),
+		SwitchContext:  c["switch_context"].(bool),
+		OutputPath:     c["output_path"].(string),
+		ContextName:    c["context_name"].(string),
+		ServerOverride: c["server_override"].(string),
+	}
+}
+
 func flattenRuntime(run any) v1alpha4.SimpleConfigOptionsRuntime {
 	var runtime v1alpha4.SimpleConfigOptionsRuntime
 
@@ -539,20 +1210,450 @@ func flattenRuntime(run any) v1alpha4.SimpleConfigOptionsRuntime {
 	return runtime
 }
 
-func flattenRegistries(reg any) v1alpha4.SimpleConfigRegistries {
+func flattenRegistries(reg any) (v1alpha4.SimpleConfigRegistries, error) {
 	regs := reg.(*schema.Set).List()
 	if len(regs) == 0 || regs[0] == nil {
-		return v1alpha4.SimpleConfigRegistries{}
+		return v1alpha4.SimpleConfigRegistries{}, nil
 	}
 
 	r := regs[0].(map[string]any)
 
-	create := r["create"].(bool)
-	if !create {
-		return v1alpha4.SimpleConfigRegistries{
-			Use: utils.GetSlice(r["use"].([]any)),
+	registries := v1alpha4.SimpleConfigRegistries{
+		Use:    utils.GetSlice(r["use"].([]any)),
+		Config: r["config"].(string),
+	}
+
+	if r["create"].(bool) {
+		registries.Create = &v1alpha4.SimpleConfigRegistryCreateConfig{
+			Name: r["name"].(string),
+			Host: r["host"].(string),
+		}
+
+		if hostPort := r["host_port"].(int); hostPort != 0 {
+			registries.Create.HostPort = strconv.Itoa(hostPort)
+		}
+	}
+
+	if k3sRegistries := flattenK3sRegistries(r); k3sRegistries != nil {
+		registriesConfig, err := registry.RenderK3sRegistries(k3sRegistries)
+		if err != nil {
+			return registries, err
+		}
+
+		registries.Config = registriesConfig
+	}
+
+	return registries, nil
+}
+
+// flattenK3sRegistries builds the registries.yaml of k3s from the mirrors and configs blocks, nil is returned when none of them are set.
+func flattenK3sRegistries(r map[string]any) *k3s.Registry {
+	mirrors := r["mirrors"].([]any)
+	configs := r["configs"].([]any)
+
+	if len(mirrors) == 0 && len(configs) == 0 {
+		return nil
+	}
+
+	k3sRegistries := &k3s.Registry{
+		Mirrors: make(map[string]k3s.Mirror),
+		Configs: make(map[string]k3s.RegistryConfig),
+	}
+
+	for _, mirror := range mirrors {
+		m := mirror.(map[string]any)
+		k3sRegistries.Mirrors[m["registry"].(string)] = k3s.Mirror{
+			Endpoints: utils.GetSlice(m["endpoints"].([]any)),
+		}
+	}
+
+	for _, cfg := range configs {
+		c := cfg.(map[string]any)
+		registryConfig := k3s.RegistryConfig{}
+
+		if auth := c["auth"].([]any); len(auth) != 0 && auth[0] != nil {
+			a := auth[0].(map[string]any)
+			registryConfig.Auth = &k3s.AuthConfig{
+				Username:      a["username"].(string),
+				Password:      a["password"].(string),
+				Auth:          a["auth"].(string),
+				IdentityToken: a["identity_token"].(string),
+			}
+		}
+
+		if tls := c["tls"].([]any); len(tls) != 0 && tls[0] != nil {
+			t := tls[0].(map[string]any)
+			registryConfig.TLS = &k3s.TLSConfig{
+				CAFile:             t["ca_file"].(string),
+				CertFile:           t["cert_file"].(string),
+				KeyFile:            t["key_file"].(string),
+				InsecureSkipVerify: t["insecure_skip_verify"].(bool),
+			}
+		}
+
+		k3sRegistries.Configs[c["registry"].(string)] = registryConfig
+	}
+
+	return k3sRegistries
+}
+
+func flattenRegistryConfig(reg any) *cluster.RegistryConfig {
+	regs := reg.(*schema.Set).List()
+	if len(regs) == 0 || regs[0] == nil {
+		return nil
+	}
+
+	r := regs[0].(map[string]any)
+
+	if !r["create"].(bool) {
+		return nil
+	}
+
+	proxy := make(map[string]string)
+	for key, value := range r["proxy"].(map[string]any) {
+		proxy[key] = value.(string)
+	}
+
+	return &cluster.RegistryConfig{
+		Image: r["image"].(string),
+		Proxy: proxy,
+	}
+}
+
+// getClusterRegistryName returns the name of the registry created along with the cluster, which k3d defaults to k3d-<cluster>-registry.
+func getClusterRegistryName(clusterName string, reg any) string {
+	regs := reg.(*schema.Set).List()
+	if len(regs) != 0 && regs[0] != nil {
+		if name := regs[0].(map[string]any)["name"].(string); len(name) != 0 {
+			return name
+		}
+	}
+
+	return fmt.Sprintf("%s-%s-registry", types2.DefaultObjectNamePrefix, clusterName)
+}
+
+func getClusterCreatedRegistry(k3dCluster *types2.Cluster, registryName string) []map[string]any {
+	createdRegistry := make([]map[string]any, 0)
+
+	for _, node := range k3dCluster.Nodes {
+		if node.Role != types2.RegistryRole || node.Name != registryName {
+			continue
+		}
+
+		host := node.RuntimeLabels[types2.LabelRegistryHost]
+		if len(host) == 0 {
+			host = node.RuntimeLabels[types2.LabelRegistryHostIP]
+		}
+
+		hostPort, _ := strconv.Atoi(node.RuntimeLabels[types2.LabelRegistryPortExternal])
+
+		createdRegistry = append(createdRegistry, map[string]any{
+			"name":      node.Name,
+			"host":      host,
+			"host_port": hostPort,
+		})
+	}
+
+	return createdRegistry
+}
+
+// simpleConfigOverrides sets the fields of the SimpleConfig built from each of the attributes of k3d_cluster on the merged config.
+// Merging leaves out false, 0 and "" as not set, hence the attributes set in the configuration are set on the merged config explicitly.
+var simpleConfigOverrides = map[string]func(merged, overrides *v1alpha4.SimpleConfig){
+	utils.TerraformResourceServersCount: func(merged, overrides *v1alpha4.SimpleConfig) { merged.Servers = overrides.Servers },
+	utils.TerraformResourceAgentsCount:  func(merged, overrides *v1alpha4.SimpleConfig) { merged.Agents = overrides.Agents },
+	utils.TerraformResourceImage:        func(merged, overrides *v1alpha4.SimpleConfig) { merged.Image = overrides.Image },
+	utils.TerraformResourceNetwork:      func(merged, overrides *v1alpha4.SimpleConfig) { merged.Network = overrides.Network },
+	utils.TerraformResourceSubnet:       func(merged, overrides *v1alpha4.SimpleConfig) { merged.Subnet = overrides.Subnet },
+	utils.TerraformResourceClusterToken: func(merged, overrides *v1alpha4.SimpleConfig) { merged.ClusterToken = overrides.ClusterToken },
+	utils.TerraformKubeAPI:              func(merged, overrides *v1alpha4.SimpleConfig) { merged.ExposeAPI = overrides.ExposeAPI },
+	utils.TerraformResourceVolumes:      func(merged, overrides *v1alpha4.SimpleConfig) { merged.Volumes = overrides.Volumes },
+	utils.TerraformResourcePorts:        func(merged, overrides *v1alpha4.SimpleConfig) { merged.Ports = overrides.Ports },
+	utils.TerraformResourceEnv:          func(merged, overrides *v1alpha4.SimpleConfig) { merged.Env = overrides.Env },
+	utils.TerraformHostAlias:            func(merged, overrides *v1alpha4.SimpleConfig) { merged.HostAliases = overrides.HostAliases },
+	utils.TerraformResourceRegistries:   func(merged, overrides *v1alpha4.SimpleConfig) { merged.Registries = overrides.Registries },
+	utils.TerraformResourceK3dOptions: func(merged, overrides *v1alpha4.SimpleConfig) {
+		merged.Options.K3dOptions = overrides.Options.K3dOptions
+	},
+	utils.TerraformResourceK3sOptions: func(merged, overrides *v1alpha4.SimpleConfig) {
+		merged.Options.K3sOptions = overrides.Options.K3sOptions
+	},
+	utils.TerraformResourceKubeConfig: func(merged, overrides *v1alpha4.SimpleConfig) {
+		merged.Options.KubeconfigOptions = overrides.Options.KubeconfigOptions
+	},
+	utils.TerraformK3dRuntime: func(merged, overrides *v1alpha4.SimpleConfig) {
+		merged.Options.Runtime = overrides.Options.Runtime
+	},
+}
+
+// mergeSimpleConfig merges simple_config into the config built from the attributes of the cluster.
+// The attributes set in the configuration override the matching fields of simple_config even when set to false, 0 or "",
+// where the blocks like k3d_options replace the matching options of simple_config as a whole.
+func mergeSimpleConfig(d attributeGetter, overrides *v1alpha4.SimpleConfig) (*v1alpha4.SimpleConfig, error) {
+	cfg, err := cluster.LoadSimpleConfig(utils.String(d.Get(utils.TerraformSimpleConfig)))
+	if err != nil {
+		return nil, err
+	}
+
+	merged, err := cluster.MergeSimpleConfig(overrides, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	rawConfig := d.GetRawConfig()
+	if rawConfig.IsNull() || !rawConfig.IsKnown() {
+		return merged, nil
+	}
+
+	for attribute, override := range simpleConfigOverrides {
+		if !rawConfig.GetAttr(attribute).IsNull() {
+			override(merged, overrides)
 		}
 	}
 
-	return v1alpha4.SimpleConfigRegistries{}
+	return merged, nil
+}
+
+func validateSimpleConfig(value any, path cty.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for _, err := range cluster.ValidateSimpleConfig(value.(string)) {
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       err.Error(),
+			AttributePath: path,
+		})
+	}
+
+	return diags
+}
+
+// clusterConfigAttributes maps the fields of the SimpleConfig to the attributes of k3d_cluster they are built from.
+var clusterConfigAttributes = map[string]string{
+	cluster.FieldVolumes:     utils.TerraformResourceVolumes,
+	cluster.FieldPorts:       utils.TerraformResourcePorts,
+	cluster.FieldEnv:         utils.TerraformResourceEnv,
+	cluster.FieldK3sOptions:  utils.TerraformResourceK3sOptions,
+	cluster.FieldRuntime:     utils.TerraformK3dRuntime,
+	cluster.FieldHostAliases: utils.TerraformHostAlias,
+	cluster.FieldKubeAPI:     utils.TerraformKubeAPI,
+	cluster.FieldRegistries:  utils.TerraformResourceRegistries,
+	cluster.FieldSubnet:      utils.TerraformResourceSubnet,
+}
+
+// customizeClusterConfigDiff builds the SimpleConfig the same way resourceClusterCreate does and validates it with k3d,
+// so that the errors k3d would otherwise report halfway through the apply are reported at plan.
+func customizeClusterConfigDiff(ctx context.Context, d *schema.ResourceDiff, meta any) error {
+	rawConfig := d.GetRawConfig()
+	if rawConfig.IsNull() {
+		return nil
+	}
+
+	// optional attributes computed by the provider are unknown at plan, hence the config is checked for values yet to be known.
+	for _, attribute := range []string{
+		utils.TerraformResourceName, utils.TerraformResourceServersCount, utils.TerraformResourceAgentsCount, utils.TerraformResourceNetwork,
+		utils.TerraformResourceSubnet, utils.TerraformKubeAPI, utils.TerraformResourceVolumes, utils.TerraformResourcePorts,
+		utils.TerraformResourceEnv, utils.TerraformHostAlias, utils.TerraformResourceRegistries, utils.TerraformResourceK3dOptions,
+		utils.TerraformResourceK3sOptions, utils.TerraformResourceK3s, utils.TerraformK3dRuntime, utils.TerraformSimpleConfig,
+	} {
+		if !rawConfig.GetAttr(attribute).IsWhollyKnown() {
+			return nil
+		}
+	}
+
+	cfg, err := flattenSimpleConfig(d)
+	if err != nil {
+		return err
+	}
+
+	simpleConfig := utils.String(d.Get(utils.TerraformSimpleConfig))
+	if len(simpleConfig) != 0 {
+		if cfg, err = mergeSimpleConfig(d, cfg); err != nil {
+			return fmt.Errorf("%s: %w", utils.TerraformSimpleConfig, err)
+		}
+	}
+
+	err = cluster.ValidateConfig(ctx, meta.(*client.Config).K3DRuntime, cfg)
+
+	var invalidConfigErr *terraformErrors.InvalidConfigError
+	if !stdErrors.As(err, &invalidConfigErr) {
+		return err
+	}
+
+	attribute := clusterConfigAttributes[invalidConfigErr.Field]
+	if len(simpleConfig) != 0 {
+		return fmt.Errorf("%s (or %s of %s): %w", attribute, invalidConfigErr.Field, utils.TerraformSimpleConfig, invalidConfigErr.Err)
+	}
+
+	return fmt.Errorf("%s: %w", attribute, invalidConfigErr.Err)
+}
+
+// customizeClusterSimpleConfigDiff plans the cluster to be recreated when the contents of simple_config change,
+// which are otherwise not seen when only the file simple_config points to is edited.
+func customizeClusterSimpleConfigDiff(_ context.Context, d *schema.ResourceDiff, _ any) error {
+	if !d.NewValueKnown(utils.TerraformSimpleConfig) {
+		return d.SetNewComputed(utils.TerraformSimpleConfigHash)
+	}
+
+	simpleConfigHash, err := getSimpleConfigHash(utils.String(d.Get(utils.TerraformSimpleConfig)))
+	if err != nil {
+		return fmt.Errorf("%s: %w", utils.TerraformSimpleConfig, err)
+	}
+
+	oldHash := utils.String(d.Get(utils.TerraformSimpleConfigHash))
+	if simpleConfigHash == oldHash {
+		return nil
+	}
+
+	if err = d.SetNew(utils.TerraformSimpleConfigHash, simpleConfigHash); err != nil {
+		return err
+	}
+
+	// the cluster is either yet to be created or recreated anyway for the change in simple_config,
+	// clusters created before the hash was recorded adopt the hash of the current contents.
+	if len(d.Id()) == 0 || d.HasChange(utils.TerraformSimpleConfig) || len(oldHash) == 0 {
+		return nil
+	}
+
+	return d.ForceNew(utils.TerraformSimpleConfigHash)
+}
+
+// getSimpleConfigHash returns the sha256 of the contents of simple_config, which is empty when simple_config is not set.
+func getSimpleConfigHash(simpleConfig string) (string, error) {
+	if len(simpleConfig) == 0 {
+		return "", nil
+	}
+
+	return cluster.HashSimpleConfig(simpleConfig)
+}
+
+// customizeClusterImageDiff plans the upgrade of the cluster to the image of kubernetes_version of the provider,
+// when the image is set neither on the cluster nor in its simple_config.
+func customizeClusterImageDiff(_ context.Context, d *schema.ResourceDiff, meta any) error {
+	if len(d.Id()) == 0 || d.HasChange(utils.TerraformResourceImage) {
+		return nil
+	}
+
+	rawConfig := d.GetRawConfig()
+	if rawConfig.IsNull() || !rawConfig.GetAttr(utils.TerraformResourceImage).IsNull() ||
+		!rawConfig.GetAttr(utils.TerraformSimpleConfig).IsWhollyKnown() {
+		return nil
+	}
+
+	if simpleConfig := utils.String(d.Get(utils.TerraformSimpleConfig)); len(simpleConfig) != 0 {
+		// invalid simple_config is reported by customizeClusterConfigDiff.
+		cfg, err := cluster.LoadSimpleConfig(simpleConfig)
+		if err != nil || len(cfg.Image) != 0 {
+			return nil
+		}
+	}
+
+	image := meta.(*client.Config).GetK3dImage()
+	if image == utils.String(d.Get(utils.TerraformResourceImage)) {
+		return nil
+	}
+
+	return d.SetNew(utils.TerraformResourceImage, image)
+}
+
+// customizeClusterVolumesDiff validates the sources of the volumes before the cluster is created.
+func customizeClusterVolumesDiff(_ context.Context, d *schema.ResourceDiff, _ any) error {
+	if !d.NewValueKnown(utils.TerraformResourceVolumes) || !d.HasChange(utils.TerraformResourceVolumes) {
+		return nil
+	}
+
+	for _, volume := range flattenClusterVolumes(d.Get(utils.TerraformResourceVolumes)) {
+		if err := volume.Validate(); err != nil {
+			return fmt.Errorf("%s: %w", utils.TerraformResourceVolumes, err)
+		}
+	}
+
+	return nil
+}
+
+// customizeClusterRegistriesDiff validates the registries.yaml rendered from registries block at plan time.
+func customizeClusterRegistriesDiff(_ context.Context, d *schema.ResourceDiff, _ any) error {
+	if !d.NewValueKnown(utils.TerraformResourceRegistries) {
+		return nil
+	}
+
+	regs := d.Get(utils.TerraformResourceRegistries).(*schema.Set).List()
+	if len(regs) == 0 || regs[0] == nil {
+		return nil
+	}
+
+	r := regs[0].(map[string]any)
+
+	k3sRegistries := flattenK3sRegistries(r)
+	if k3sRegistries == nil {
+		return nil
+	}
+
+	if len(r["config"].(string)) != 0 {
+		return fmt.Errorf("%w: config cannot be set along with mirrors or configs", terraformErrors.ErrInvalidRegistriesConfig)
+	}
+
+	validationErrors := registry.ValidateK3sRegistries(k3sRegistries)
+	if len(validationErrors) != 0 {
+		return stdErrors.Join(validationErrors...)
+	}
+
+	return nil
+}
+
+func validatePortRange(value any, path cty.Path) diag.Diagnostics {
+	if err := cluster.ValidatePortRange(value.(string)); err != nil {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       err.Error(),
+			AttributePath: path,
+		}}
+	}
+
+	return nil
+}
+
+// validateHostPortRange validates the host port same as validatePortRange, except 0 which was used earlier to let the runtime pick the port.
+func validateHostPortRange(value any, path cty.Path) diag.Diagnostics {
+	if value.(string) == "0" {
+		return nil
+	}
+
+	return validatePortRange(value, path)
+}
+
+func validateNodePoolName(value any, path cty.Path) diag.Diagnostics {
+	if err := k3dNode.ValidateNodePoolName(value.(string)); err != nil {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       err.Error(),
+			AttributePath: path,
+		}}
+	}
+
+	return nil
+}
+
+func validateNodeTaint(value any, path cty.Path) diag.Diagnostics {
+	if err := k3dNode.ValidateTaint(value.(string)); err != nil {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       err.Error(),
+			AttributePath: path,
+		}}
+	}
+
+	return nil
+}
+
+func validateContextName(value any, path cty.Path) diag.Diagnostics {
+	if err := k3dKube.ValidateContextName(value.(string)); err != nil {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       err.Error(),
+			AttributePath: path,
+		}}
+	}
+
+	return nil
 }
@@ -1,9 +1,23 @@
 package provider
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
 	"testing"
+	"time"
 
+	"github.com/docker/go-connections/nat"
+	"github.com/hashicorp/go-cty/cty"
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	terraformErrors "github.com/nikhilsbhat/terraform-provider-k3d/pkg/errors"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/utils"
+	"github.com/rancher/k3d/v5/pkg/config/v1alpha4"
+	"github.com/rancher/k3d/v5/pkg/runtimes"
+	types2 "github.com/rancher/k3d/v5/pkg/types"
+	"inet.af/netaddr"
 )
 
 func TestFlattenK3SOptionsWithExtraArgsBlocks(t *testing.T) {
@@ -48,3 +62,528 @@ func TestFlattenK3SOptionsWithExtraArgsBlocks(t *testing.T) {
 		t.Fatalf("expected node label %q, got %q", want, got)
 	}
 }
+
+func TestFlattenKubeAPI(t *testing.T) {
+	kubeAPI := schema.NewSet(resourceClusterKubeAPIHash, []any{
+		map[string]any{
+			"host":      "k3d.local",
+			"host_ip":   "0.0.0.0",
+			"host_port": 0,
+			"tls_san":   []any{"api.example.com"},
+		},
+	})
+
+	exposureOpts := flattenKubeAPI(kubeAPI)
+	if got, want := exposureOpts.HostPort, ""; got != want {
+		t.Fatalf("expected host port %q, got %q", want, got)
+	}
+
+	extraArgs := flattenKubeAPITLSSANs(kubeAPI)
+	if got, want := len(extraArgs), 1; got != want {
+		t.Fatalf("expected %d extra args, got %d", want, got)
+	}
+
+	if got, want := extraArgs[0].Arg, "--tls-san=api.example.com"; got != want {
+		t.Fatalf("expected extra arg %q, got %q", want, got)
+	}
+
+	if got, want := extraArgs[0].NodeFilters[0], "server:*"; got != want {
+		t.Fatalf("expected extra arg node filter %q, got %q", want, got)
+	}
+}
+
+func TestFlattenSimpleConfigWithK3s(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCluster().Schema, map[string]any{
+		"name": "test",
+		"k3s": []any{
+			map[string]any{
+				"disable":      []any{"traefik"},
+				"cluster_cidr": "10.42.0.0/16",
+			},
+		},
+	})
+
+	cfg, err := flattenSimpleConfig(d)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if got, want := len(cfg.Options.K3sOptions.ExtraArgs), 2; got != want {
+		t.Fatalf("expected %d extra args, got %d", want, got)
+	}
+
+	if got, want := cfg.Options.K3sOptions.ExtraArgs[0].Arg, "--disable=traefik"; got != want {
+		t.Fatalf("expected extra arg %q, got %q", want, got)
+	}
+
+	if got, want := cfg.Options.K3sOptions.ExtraArgs[1].NodeFilters[0], "server:*"; got != want {
+		t.Fatalf("expected extra arg node filter %q, got %q", want, got)
+	}
+
+	d = schema.TestResourceDataRaw(t, resourceCluster().Schema, map[string]any{
+		"name": "test",
+		"k3s":  []any{map[string]any{"cluster_cidr": "10.42.0.0/16"}},
+		"k3s_options": []any{
+			map[string]any{
+				"extra_args": []any{
+					map[string]any{"key": "--cluster-cidr", "value": "10.52.0.0/16", "node_filters": []any{"server:*"}},
+				},
+			},
+		},
+	})
+
+	if _, err = flattenSimpleConfig(d); !errors.Is(err, terraformErrors.ErrConflictingK3sArg) {
+		t.Fatalf("expected error %v, got %v", terraformErrors.ErrConflictingK3sArg, err)
+	}
+}
+
+// clusterDataWithRawConfig holds the raw config the resource data of the cluster is built from, which TestResourceDataRaw leaves out.
+type clusterDataWithRawConfig struct {
+	*schema.ResourceData
+	rawConfig cty.Value
+}
+
+func (d *clusterDataWithRawConfig) GetRawConfig() cty.Value {
+	return d.rawConfig
+}
+
+func mergeTestSimpleConfig(t *testing.T, raw map[string]any) *v1alpha4.SimpleConfig {
+	t.Helper()
+
+	content, err := json.Marshal(raw)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	rawConfig, err := ctyjson.Unmarshal(content, resourceCluster().CoreConfigSchema().ImpliedType())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	d := &clusterDataWithRawConfig{ResourceData: schema.TestResourceDataRaw(t, resourceCluster().Schema, raw), rawConfig: rawConfig}
+
+	cfg, err := flattenSimpleConfig(d)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if cfg, err = mergeSimpleConfig(d, cfg); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	return cfg
+}
+
+func TestMergeSimpleConfigWithK3dOptions(t *testing.T) {
+	simpleConfig := "options:\n  k3d:\n    wait: false\n    timeout: 90s\n"
+
+	merge := func(raw map[string]any) *v1alpha4.SimpleConfig {
+		return mergeTestSimpleConfig(t, raw)
+	}
+
+	cfg := merge(map[string]any{"name": "test", "simple_config": simpleConfig})
+	if cfg.Options.K3dOptions.Wait || cfg.Options.K3dOptions.Timeout != 90*time.Second {
+		t.Fatalf("expected k3d options of simple_config to be used without k3d_options, got %+v", cfg.Options.K3dOptions)
+	}
+
+	cfg = merge(map[string]any{"name": "test", "simple_config": "servers: 1\n"})
+	if !cfg.Options.K3dOptions.Wait {
+		t.Fatalf("expected to wait for the cluster when neither k3d_options nor simple_config sets it, got %+v", cfg.Options.K3dOptions)
+	}
+
+	cfg = merge(map[string]any{
+		"name":          "test",
+		"simple_config": "options:\n  k3d:\n    timeout: 90s\n",
+		"k3d_options":   []any{map[string]any{"wait": false}},
+	})
+	if cfg.Options.K3dOptions.Wait || cfg.Options.K3dOptions.Timeout != 0 {
+		t.Fatalf("expected k3d_options to replace the k3d options of simple_config, got %+v", cfg.Options.K3dOptions)
+	}
+}
+
+func TestMergeSimpleConfigWithFalseAttributes(t *testing.T) {
+	simpleConfig := "servers: 3\nimage: rancher/k3s:v1.24.4-k3s1\n" +
+		"options:\n  k3d:\n    disableLoadbalancer: true\n  kubeconfig:\n    updateDefaultKubeconfig: true\n    switchCurrentContext: true\n"
+
+	cfg := mergeTestSimpleConfig(t, map[string]any{"name": "test", "simple_config": simpleConfig})
+	if !cfg.Options.K3dOptions.DisableLoadbalancer || !cfg.Options.KubeconfigOptions.UpdateDefaultKubeconfig || cfg.Servers != 3 {
+		t.Fatalf("expected the fields of simple_config to be used when the attributes are not set, got %+v", cfg)
+	}
+
+	cfg = mergeTestSimpleConfig(t, map[string]any{
+		"name":          "test",
+		"simple_config": simpleConfig,
+		"servers_count": 1,
+		"k3d_options":   []any{map[string]any{"no_loadbalancer": false}},
+		"kube_config":   []any{map[string]any{"update_default": false, "switch_context": false}},
+	})
+
+	if cfg.Options.K3dOptions.DisableLoadbalancer {
+		t.Fatalf("expected k3d_options.no_loadbalancer set to false to override simple_config, got %+v", cfg.Options.K3dOptions)
+	}
+
+	if cfg.Options.KubeconfigOptions.UpdateDefaultKubeconfig || cfg.Options.KubeconfigOptions.SwitchCurrentContext {
+		t.Fatalf("expected kube_config set to false to override simple_config, got %+v", cfg.Options.KubeconfigOptions)
+	}
+
+	if got, want := cfg.Servers, 1; got != want {
+		t.Fatalf("expected servers_count to override simple_config with %d servers, got %d", want, got)
+	}
+
+	if got, want := cfg.Image, "rancher/k3s:v1.24.4-k3s1"; got != want {
+		t.Fatalf("expected image of simple_config %q to be used when image is not set, got %q", want, got)
+	}
+}
+
+func TestFlattenRegistriesWithCreate(t *testing.T) {
+	registriesSchema := resourceCluster().Schema["registries"]
+	registriesHash := schema.HashResource(registriesSchema.Elem.(*schema.Resource))
+	registries := schema.NewSet(registriesHash, []any{
+		map[string]any{
+			"create":    true,
+			"name":      "registry.localhost",
+			"host":      "127.0.0.1",
+			"host_port": 5000,
+			"image":     "docker.io/library/registry:2",
+			"proxy":     map[string]any{"remoteURL": "https://registry-1.docker.io"},
+			"use":       []any{"k3d-shared-registry:5000"},
+			"config":    "mirrors: {}",
+			"mirrors":   []any{},
+			"configs":   []any{},
+		},
+	})
+
+	registriesCfg, err := flattenRegistries(registries)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if registriesCfg.Create == nil {
+		t.Fatal("expected registry to be created")
+	}
+
+	if got, want := registriesCfg.Create.HostPort, "5000"; got != want {
+		t.Fatalf("expected registry host port %q, got %q", want, got)
+	}
+
+	if got, want := registriesCfg.Config, "mirrors: {}"; got != want {
+		t.Fatalf("expected registry config %q, got %q", want, got)
+	}
+
+	if got, want := len(registriesCfg.Use), 1; got != want {
+		t.Fatalf("expected %d registries to be used, got %d", want, got)
+	}
+
+	registryCfg := flattenRegistryConfig(registries)
+	if got, want := registryCfg.Proxy["remoteURL"], "https://registry-1.docker.io"; got != want {
+		t.Fatalf("expected registry proxy remoteURL %q, got %q", want, got)
+	}
+
+	if got, want := getClusterRegistryName("test", registries), "registry.localhost"; got != want {
+		t.Fatalf("expected registry name %q, got %q", want, got)
+	}
+}
+
+func TestFlattenRegistriesWithMirrorsAndConfigs(t *testing.T) {
+	registriesSchema := resourceCluster().Schema["registries"]
+	registriesHash := schema.HashResource(registriesSchema.Elem.(*schema.Resource))
+	registries := schema.NewSet(registriesHash, []any{
+		map[string]any{
+			"create":    false,
+			"name":      "",
+			"host":      "",
+			"host_port": 0,
+			"image":     "",
+			"proxy":     map[string]any{},
+			"use":       []any{},
+			"config":    "",
+			"mirrors": []any{
+				map[string]any{
+					"registry":  "docker.io",
+					"endpoints": []any{"https://mirror.example.com"},
+				},
+			},
+			"configs": []any{
+				map[string]any{
+					"registry": "mirror.example.com",
+					"auth": []any{
+						map[string]any{"username": "admin", "password": "secret", "auth": "", "identity_token": ""},
+					},
+					"tls": []any{
+						map[string]any{"ca_file": "/etc/ssl/ca.pem", "cert_file": "", "key_file": "", "insecure_skip_verify": false},
+					},
+				},
+			},
+		},
+	})
+
+	registriesCfg, err := flattenRegistries(registries)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := `mirrors:
+  docker.io:
+    endpoint:
+    - https://mirror.example.com
+configs:
+  mirror.example.com:
+    auth:
+      username: admin
+      password: secret
+      auth: ""
+      identity_token: ""
+    tls:
+      ca_file: /etc/ssl/ca.pem
+      cert_file: ""
+      key_file: ""
+      insecure_skip_verify: false
+auths: {}
+`
+	if got := registriesCfg.Config; got != expected {
+		t.Fatalf("expected registries config %q, got %q", expected, got)
+	}
+}
+
+func TestGetClusterNodeDetails(t *testing.T) {
+	node := &types2.Node{
+		Name:   "k3d-test-server-0",
+		Role:   types2.ServerRole,
+		Memory: "1GiB",
+		State:  types2.NodeState{Running: true, Status: "running"},
+		IP:     types2.NodeIP{IP: netaddr.MustParseIP("172.18.0.2")},
+		Ports: nat.PortMap{
+			"80/tcp":   []nat.PortBinding{{HostIP: "0.0.0.0", HostPort: "8080"}},
+			"6443/tcp": []nat.PortBinding{{HostIP: "0.0.0.0", HostPort: "6445"}},
+		},
+	}
+
+	details := getClusterNodeDetails(node, "rancher/k3s:v1.24.4-k3s1")
+
+	if got, want := details["ip"], "172.18.0.2"; got != want {
+		t.Fatalf("expected ip %q, got %q", want, got)
+	}
+
+	if got, want := details["role"], "server"; got != want {
+		t.Fatalf("expected role %q, got %q", want, got)
+	}
+
+	ports := details["ports"].([]map[string]any)
+	if got, want := len(ports), 2; got != want {
+		t.Fatalf("expected %d ports, got %d", want, got)
+	}
+
+	if got, want := ports[1]["container_port"], "80/tcp"; got != want {
+		t.Fatalf("expected container port %q, got %q", want, got)
+	}
+}
+
+func TestFlattenWaitFor(t *testing.T) {
+	readinessCfg, err := flattenWaitFor([]any{
+		map[string]any{
+			"nodes_ready":   true,
+			"nodes_timeout": "2m",
+			"interval":      "2s",
+			"deployment": []any{
+				map[string]any{"namespace": "kube-system", "name": "coredns", "timeout": "90s"},
+			},
+			"daemonset": []any{
+				map[string]any{"namespace": "kube-system", "name": "svclb-traefik", "timeout": "1m"},
+			},
+		},
+	}, 3)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if got, want := readinessCfg.Nodes, 3; got != want {
+		t.Fatalf("expected %d nodes, got %d", want, got)
+	}
+
+	if got, want := len(readinessCfg.Workloads), 2; got != want {
+		t.Fatalf("expected %d workloads, got %d", want, got)
+	}
+
+	if got, want := readinessCfg.Workloads[1].Kind, "daemonset"; got != want {
+		t.Fatalf("expected workload kind %q, got %q", want, got)
+	}
+}
+
+func TestGetAddonsHash(t *testing.T) {
+	addons := flattenAddons([]any{
+		map[string]any{"name": "namespace", "manifest": "kind: Namespace", "helm_chart": []any{}},
+		map[string]any{
+			"name":     "traefik",
+			"manifest": "",
+			"helm_chart": []any{
+				map[string]any{"chart": "traefik", "repo": "", "version": "", "target_namespace": "", "values": ""},
+			},
+		},
+	})
+
+	hashes, err := getAddonsHash(addons)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if got, want := len(hashes), 2; got != want {
+		t.Fatalf("expected %d hashes, got %d", want, got)
+	}
+
+	if _, err = getAddonsHash(append(addons, addons[0])); !errors.Is(err, terraformErrors.ErrInvalidAddon) {
+		t.Fatalf("expected %v for duplicate addon, got %v", terraformErrors.ErrInvalidAddon, err)
+	}
+}
+
+func TestFlattenClusterNodePools(t *testing.T) {
+	nodePools := flattenClusterNodePools([]any{
+		map[string]any{
+			"name":    "gpu",
+			"count":   2,
+			"image":   "",
+			"memory":  "2g",
+			"labels":  map[string]any{"accelerator": "nvidia"},
+			"taints":  []any{"gpu=true:NoSchedule"},
+			"env":     map[string]any{"LOG": "debug", "HTTP_PROXY": "http://proxy:3128"},
+			"volumes": []any{map[string]any{"source": "/data", "destination": "/data", "mode": "ro"}},
+		},
+	}, "test", "rancher/k3s:v1.24.4-k3s1", v1alpha4.SimpleConfigOptionsK3d{})
+
+	if got, want := len(nodePools), 1; got != want {
+		t.Fatalf("expected %d node pools, got %d", want, got)
+	}
+
+	pool := nodePools[0]
+	if got, want := pool.Name[0], "k3d-test-gpu"; got != want {
+		t.Fatalf("expected prefix %q, got %q", want, got)
+	}
+
+	if got, want := pool.Image, "rancher/k3s:v1.24.4-k3s1"; got != want {
+		t.Fatalf("expected image of the cluster %q, got %q", want, got)
+	}
+
+	if got, want := pool.EnvironmentVariables, []string{"HTTP_PROXY=http://proxy:3128", "LOG=debug"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected env %v, got %v", want, got)
+	}
+
+	if got, want := pool.Volumes, []string{"/data:/data:ro"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected volumes %v, got %v", want, got)
+	}
+
+	scaled := *pool
+	scaled.Count = 5
+
+	if isNodePoolChanged(pool, &scaled) {
+		t.Fatalf("expected node pool scaled to be left unchanged")
+	}
+
+	scaled.Memory = "4g"
+
+	if !isNodePoolChanged(pool, &scaled) {
+		t.Fatalf("expected node pool with memory changed to be changed")
+	}
+}
+
+func TestGetClusterNodePools(t *testing.T) {
+	agent := func(name, pool string) *types2.Node {
+		return &types2.Node{Name: name, Role: types2.AgentRole, RuntimeLabels: map[string]string{utils.TerraformNodePoolLabel: pool}}
+	}
+
+	k3dCluster := &types2.Cluster{
+		Name: "test",
+		Nodes: []*types2.Node{
+			{Name: "k3d-test-server-0", Role: types2.ServerRole},
+			{Name: "k3d-test-agent-0", Role: types2.AgentRole},
+			agent("k3d-test-gpu-0", "gpu"),
+			agent("k3d-test-gpu-1", "gpu"),
+			agent("k3d-test-web-0", "web"),
+		},
+	}
+
+	nodePools := getClusterNodePools(k3dCluster, []any{
+		map[string]any{"name": "gpu", "count": 3},
+		map[string]any{"name": "web", "count": 1},
+		map[string]any{"name": "batch", "count": 1},
+	})
+
+	for index, want := range []int{2, 1, 0} {
+		if got := nodePools[index].(map[string]any)["count"]; got != want {
+			t.Fatalf("expected count %d for node pool %d, got %v", want, index, got)
+		}
+	}
+}
+
+// stubRuntime returns the nodes or the error it holds for the nodes looked up, the rest of the runtime is not implemented.
+type stubRuntime struct {
+	runtimes.Runtime
+	nodes []*types2.Node
+	err   error
+}
+
+func (runtime *stubRuntime) GetNodesByLabel(_ context.Context, _ map[string]string) ([]*types2.Node, error) {
+	return runtime.nodes, runtime.err
+}
+
+func TestResourceClusterReadWithoutNodes(t *testing.T) {
+	selectedRuntime := runtimes.SelectedRuntime
+	defer func() { runtimes.SelectedRuntime = selectedRuntime }()
+
+	t.Run("should keep the cluster in state when the runtime errors", func(t *testing.T) {
+		runtimes.SelectedRuntime = &stubRuntime{err: errors.New("cannot connect to the docker daemon")}
+
+		d := schema.TestResourceDataRaw(t, resourceCluster().Schema, map[string]any{"name": "test"})
+		d.SetId("test")
+
+		diags := resourceClusterRead(context.Background(), d, nil)
+		if !diags.HasError() {
+			t.Fatal("expected the runtime error to be returned")
+		}
+
+		if got, want := diags[0].Summary, "fetching nodes of cluster 'test' errored with cannot connect to the docker daemon"; got != want {
+			t.Fatalf("expected error %q, got %q", want, got)
+		}
+
+		if got, want := d.Id(), "test"; got != want {
+			t.Fatalf("expected cluster to be kept in state with ID %q, got %q", want, got)
+		}
+	})
+
+	t.Run("should remove the cluster from state when the runtime has none of its nodes", func(t *testing.T) {
+		runtimes.SelectedRuntime = &stubRuntime{nodes: []*types2.Node{}}
+
+		d := schema.TestResourceDataRaw(t, resourceCluster().Schema, map[string]any{"name": "test"})
+		d.SetId("test")
+
+		if diags := resourceClusterRead(context.Background(), d, nil); diags.HasError() {
+			t.Fatalf("expected no error, got %v", diags)
+		}
+
+		if d.Id() != "" {
+			t.Fatalf("expected cluster to be removed from state, got ID %q", d.Id())
+		}
+	})
+}
+
+func TestResourceClusterUpdatableAttributes(t *testing.T) {
+	// attributes changed in place, every one of them is handled by resourceClusterUpdate.
+	updatable := map[string]bool{
+		"addons":        true,
+		"agents_count":  true,
+		"config_yaml":   true, // not used by the provider.
+		"image":         true,
+		"kube_config":   true,
+		"node_pool":     true,
+		"servers_count": true,
+		"wait_for":      true,
+	}
+
+	for name, attribute := range resourceCluster().Schema {
+		if attribute.ForceNew || !(attribute.Optional || attribute.Required) {
+			continue
+		}
+
+		if !updatable[name] {
+			t.Fatalf("expected %s to be either ForceNew or handled by resourceClusterUpdate", name)
+		}
+	}
+}
@@ -0,0 +1,375 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/client"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/k3d/cluster"
+	k3dNode "github.com/nikhilsbhat/terraform-provider-k3d/pkg/k3d/node"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/utils"
+	k3dClient "github.com/rancher/k3d/v5/pkg/client"
+	K3D "github.com/rancher/k3d/v5/pkg/types"
+	"github.com/thoas/go-funk"
+)
+
+// resourceClusterImport rebuilds the cluster configuration from the runtime state of the nodes of an existing cluster,
+// so that clusters created with k3d cli can be brought under terraform.
+func resourceClusterImport(ctx context.Context, d *schema.ResourceData, meta any) ([]*schema.ResourceData, error) {
+	defaultConfig := meta.(*client.Config)
+
+	clusterName := d.Id()
+
+	k3dCluster, err := k3dClient.ClusterGet(ctx, defaultConfig.K3DRuntime, &K3D.Cluster{Name: clusterName})
+	if err != nil {
+		return nil, fmt.Errorf("fetching cluster '%s' to import errored with %w", clusterName, err)
+	}
+
+	inspector, err := k3dNode.NewInspector(defaultConfig.K3DRuntime)
+	if err != nil {
+		return nil, fmt.Errorf("inspecting nodes of cluster '%s' to import errored with %w", clusterName, err)
+	}
+
+	defer inspector.Close()
+
+	attributes, err := getClusterAttributes(ctx, inspector, k3dCluster)
+	if err != nil {
+		return nil, err
+	}
+
+	for attribute, value := range attributes {
+		if err = d.Set(attribute, value); err != nil {
+			return nil, fmt.Errorf("setting %s errored with %w", attribute, err)
+		}
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// getClusterAttributes rebuilds the attributes of k3d_cluster from the runtime state of the nodes of the cluster.
+func getClusterAttributes(ctx context.Context, inspector *k3dNode.Inspector, k3dCluster *K3D.Cluster) (map[string]any, error) {
+	servers := getClusterNodesByRole(k3dCluster, K3D.ServerRole)
+	agents := getClusterNodesByRole(k3dCluster, K3D.AgentRole)
+
+	if len(servers) == 0 {
+		return nil, fmt.Errorf("cluster '%s' has no servers to read its config from", k3dCluster.Name)
+	}
+
+	image, err := inspector.GetNodeImage(ctx, servers[0].Name)
+	if err != nil {
+		return nil, fmt.Errorf("fetching image of node '%s' errored with %w", servers[0].Name, err)
+	}
+
+	return map[string]any{
+		utils.TerraformResourceName:       k3dCluster.Name,
+		utils.TerraformResourceImage:      image.Reference,
+		utils.TerraformResourceSubnet:     servers[0].RuntimeLabels[K3D.LabelNetworkIPRange],
+		utils.TerraformResourceVolumes:    getClusterVolumes(k3dCluster.ImageVolume, servers, agents),
+		utils.TerraformResourcePorts:      getClusterPorts(k3dCluster.ServerLoadBalancer, servers, agents),
+		utils.TerraformResourceEnv:        getClusterEnvVars(image.Env, servers, agents),
+		utils.TerraformResourceK3sOptions: getClusterK3sOptions(servers, agents),
+		utils.TerraformKubeAPI:            getClusterKubeAPI(servers[0]),
+	}, nil
+}
+
+// getClusterNodesByRole returns the nodes of the specified role that were created along with the cluster, sorted by their index.
+func getClusterNodesByRole(k3dCluster *K3D.Cluster, role K3D.Role) []*K3D.Node {
+	nodesByName := make(map[string]*K3D.Node)
+	names := make([]string, 0)
+
+	for _, node := range k3dCluster.Nodes {
+		if node.Role == role {
+			nodesByName[node.Name] = node
+			names = append(names, node.Name)
+		}
+	}
+
+	nodes := make([]*K3D.Node, 0)
+	for _, name := range k3dNode.IndexedNodes(getClusterNodePrefix(k3dCluster.Name, role), names) {
+		nodes = append(nodes, nodesByName[name])
+	}
+
+	return nodes
+}
+
+// getClusterVolumes returns the volumes mounted to the nodes, except the image volume which is managed by k3d.
+func getClusterVolumes(imageVolume string, servers, agents []*K3D.Node) []any {
+	imageVolumeMount := fmt.Sprintf("%s:%s", imageVolume, K3D.DefaultImageVolumeMountPath)
+
+	volumes, nodesByVolume := groupNodesByValue(servers, agents, func(node *K3D.Node) []string {
+		return funk.FilterString(node.Volumes, func(volume string) bool {
+			return volume != imageVolumeMount
+		})
+	})
+
+	k3dVolumes := make([]any, 0, len(volumes))
+
+	for _, volume := range volumes {
+		source, destination, mode := getVolumeMount(volume)
+
+		volumeType := cluster.VolumeTypeBind
+		if len(source) != 0 && !strings.ContainsAny(source, `/\`) {
+			volumeType = cluster.VolumeTypeNamed
+		}
+
+		k3dVolumes = append(k3dVolumes, map[string]any{
+			"source":       source,
+			"destination":  destination,
+			"mode":         mode,
+			"type":         volumeType,
+			"node_filters": getNodeFilters(nodesByVolume[volume], servers, agents),
+		})
+	}
+
+	return k3dVolumes
+}
+
+// getVolumeMount splits the volume mounted to the node, [SOURCE:]DEST[:MODE], into its parts.
+func getVolumeMount(volume string) (string, string, string) {
+	parts := strings.Split(volume, ":")
+
+	switch len(parts) {
+	case 1:
+		return "", parts[0], ""
+	case 2:
+		if strings.HasPrefix(parts[1], "/") {
+			return parts[0], parts[1], ""
+		}
+
+		return "", parts[0], parts[1]
+	default:
+		return parts[0], parts[1], strings.Join(parts[2:], ":")
+	}
+}
+
+// getClusterPorts returns the ports exposed via the loadbalancer and the ones directly mapped from the nodes,
+// except the port of the kubernetes API which is managed by kube_api.
+func getClusterPorts(loadBalancer *K3D.Loadbalancer, servers, agents []*K3D.Node) []any {
+	k3dPorts := make([]any, 0)
+
+	if loadBalancer != nil && loadBalancer.Node != nil {
+		for port, bindings := range loadBalancer.Node.Ports {
+			if port.Port() == K3D.DefaultAPIPort {
+				continue
+			}
+
+			nodeFilters := []string{string(K3D.LoadBalancerRole)}
+			if loadBalancer.Config != nil {
+				if targets := loadBalancer.Config.Ports[fmt.Sprintf("%s.%s", port.Port(), port.Proto())]; len(targets) != 0 {
+					nodeFilters = getNodeFilters(targets, servers, agents)
+				}
+			}
+
+			for _, binding := range bindings {
+				k3dPorts = append(k3dPorts, getPortMapping(binding.HostIP, binding.HostPort, port.Int(), port.Proto(), nodeFilters))
+			}
+		}
+	}
+
+	for _, group := range [][]*K3D.Node{servers, agents} {
+		for index, node := range group {
+			for port, bindings := range node.Ports {
+				if port.Port() == K3D.DefaultAPIPort {
+					continue
+				}
+
+				nodeFilters := []string{fmt.Sprintf("%s:%d:direct", node.Role, index)}
+				for _, binding := range bindings {
+					k3dPorts = append(k3dPorts, getPortMapping(binding.HostIP, binding.HostPort, port.Int(), port.Proto(), nodeFilters))
+				}
+			}
+		}
+	}
+
+	return k3dPorts
+}
+
+func getPortMapping(host, hostPort string, containerPort int, protocol string, nodeFilters []string) map[string]any {
+	if hostPort == "0" {
+		hostPort = ""
+	}
+
+	return map[string]any{
+		"host":           host,
+		"host_port":      hostPort,
+		"container_port": strconv.Itoa(containerPort),
+		"protocol":       strings.ToUpper(protocol),
+		"node_filters":   nodeFilters,
+	}
+}
+
+// getClusterEnvVars returns the environment variables set on the nodes, excluding the ones that come from the image
+// or the ones set by k3d.
+func getClusterEnvVars(imageEnvs []string, servers, agents []*K3D.Node) []any {
+	envs, nodesByEnv := groupNodesByValue(servers, agents, func(node *K3D.Node) []string {
+		return funk.FilterString(node.Env, func(env string) bool {
+			return !funk.ContainsString(imageEnvs, env) && !k3dNode.IsK3dManagedEnv(env)
+		})
+	})
+
+	k3dEnvs := make([]any, 0, len(envs))
+
+	for _, env := range envs {
+		key, value, _ := strings.Cut(env, "=")
+		k3dEnvs = append(k3dEnvs, map[string]any{
+			"key":          key,
+			"value":        value,
+			"node_filters": getNodeFilters(nodesByEnv[env], servers, agents),
+		})
+	}
+
+	return k3dEnvs
+}
+
+// getClusterK3sOptions returns the extra arguments and node labels passed on to k3s, from the commands of the nodes.
+func getClusterK3sOptions(servers, agents []*K3D.Node) []any {
+	args, nodesByArg := groupNodesByValue(servers, agents, func(node *K3D.Node) []string {
+		extraArgs, _ := getNodeK3sArgs(node)
+
+		return extraArgs
+	})
+
+	labels, nodesByLabel := groupNodesByValue(servers, agents, func(node *K3D.Node) []string {
+		_, nodeLabels := getNodeK3sArgs(node)
+
+		return nodeLabels
+	})
+
+	if len(args) == 0 && len(labels) == 0 {
+		return []any{}
+	}
+
+	extraArgs := make([]any, 0, len(args))
+	for _, arg := range args {
+		key, value, _ := strings.Cut(arg, "=")
+		extraArgs = append(extraArgs, map[string]any{
+			"key":          normalizeK3SArgKey(key),
+			"value":        value,
+			"node_filters": getNodeFilters(nodesByArg[arg], servers, agents),
+		})
+	}
+
+	nodeLabels := make([]any, 0, len(labels))
+	for _, label := range labels {
+		key, value, _ := strings.Cut(label, "=")
+		nodeLabels = append(nodeLabels, map[string]any{
+			"key":          key,
+			"value":        value,
+			"node_filters": getNodeFilters(nodesByLabel[label], servers, agents),
+		})
+	}
+
+	return []any{map[string]any{
+		"extra_args":  extraArgs,
+		"node_labels": nodeLabels,
+	}}
+}
+
+// getNodeK3sArgs returns the extra arguments and node labels passed on to k3s from the command of the node,
+// the node taints are passed on as extra arguments since k3s_options has no place of their own for them.
+func getNodeK3sArgs(node *K3D.Node) ([]string, []string) {
+	k3sArgs, k3sNodeLabels, k3sNodeTaints := k3dNode.GetK3sNodeSettings(node.Cmd)
+
+	extraArgs := make([]string, 0, len(k3sArgs)+len(k3sNodeTaints))
+	for _, taint := range k3sNodeTaints {
+		extraArgs = append(extraArgs, fmt.Sprintf("%s=%s", k3dNode.K3sNodeTaintArg, taint))
+	}
+
+	// tls-san set by tls_san of kube_api are read by getClusterKubeAPI.
+	extraArgs = append(extraArgs, funk.FilterString(k3sArgs, func(arg string) bool {
+		return !strings.HasPrefix(arg, k3dNode.K3sTLSSANArg+"=")
+	})...)
+
+	nodeLabels := make([]string, 0, len(k3sNodeLabels))
+	for _, key := range slices.Sorted(maps.Keys(k3sNodeLabels)) {
+		nodeLabels = append(nodeLabels, fmt.Sprintf("%s=%s", key, k3sNodeLabels[key]))
+	}
+
+	return extraArgs, nodeLabels
+}
+
+// getClusterKubeAPI returns how the kubernetes API is exposed, host is set only when it differs from host_ip
+// since k3d defaults it to host_ip, tls_san is read from the k3s arguments of the server.
+func getClusterKubeAPI(server *K3D.Node) []any {
+	if server.ServerOpts.KubeAPI == nil {
+		return []any{}
+	}
+
+	kubeAPI := server.ServerOpts.KubeAPI
+	hostPort, _ := strconv.Atoi(kubeAPI.Binding.HostPort)
+
+	host := kubeAPI.Host
+	if host == kubeAPI.Binding.HostIP {
+		host = ""
+	}
+
+	tlsSANs := make([]string, 0)
+
+	for _, arg := range server.Cmd {
+		if san, ok := strings.CutPrefix(arg, k3dNode.K3sTLSSANArg+"="); ok {
+			tlsSANs = append(tlsSANs, san)
+		}
+	}
+
+	return []any{map[string]any{
+		"host":      host,
+		"host_ip":   kubeAPI.Binding.HostIP,
+		"host_port": hostPort,
+		"tls_san":   tlsSANs,
+	}}
+}
+
+// groupNodesByValue collects the values returned by valuesFunc for every server and agent, in the order they are
+// first seen, along with the names of the nodes each value is present on.
+func groupNodesByValue(servers, agents []*K3D.Node, valuesFunc func(node *K3D.Node) []string) ([]string, map[string][]string) {
+	values := make([]string, 0)
+	nodesByValue := make(map[string][]string)
+
+	for _, group := range [][]*K3D.Node{servers, agents} {
+		for _, node := range group {
+			for _, value := range valuesFunc(node) {
+				if _, ok := nodesByValue[value]; !ok {
+					values = append(values, value)
+				}
+
+				nodesByValue[value] = append(nodesByValue[value], node.Name)
+			}
+		}
+	}
+
+	return values, nodesByValue
+}
+
+// getNodeFilters translates the names of the nodes to k3d node filters, 'role:*' is used when all nodes of a role match.
+func getNodeFilters(nodes []string, servers, agents []*K3D.Node) []string {
+	nodeFilters := make([]string, 0)
+
+	for _, group := range [][]*K3D.Node{servers, agents} {
+		indexes := make([]string, 0)
+
+		for index, node := range group {
+			if funk.ContainsString(nodes, node.Name) {
+				indexes = append(indexes, strconv.Itoa(index))
+			}
+		}
+
+		if len(indexes) == 0 {
+			continue
+		}
+
+		if len(group) > 1 && len(indexes) == len(group) {
+			nodeFilters = append(nodeFilters, fmt.Sprintf("%s:*", group[0].Role))
+
+			continue
+		}
+
+		nodeFilters = append(nodeFilters, fmt.Sprintf("%s:%s", group[0].Role, strings.Join(indexes, ",")))
+	}
+
+	return nodeFilters
+}
@@ -0,0 +1,161 @@
+package provider
+
+import (
+	"testing"
+
+	K3D "github.com/rancher/k3d/v5/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func getImportTestNodes() ([]*K3D.Node, []*K3D.Node) {
+	servers := []*K3D.Node{
+		{
+			Name:    "k3d-test-server-0",
+			Role:    K3D.ServerRole,
+			Cmd:     []string{"server", "--tls-san", "0.0.0.0", "--cluster-init", "--disable=traefik", "--node-label", "tier=control"},
+			Env:     []string{"K3S_TOKEN=secret", "K3S_KUBECONFIG_OUTPUT=/output/kubeconfig.yaml", "PATH=/bin", "LOG=debug"},
+			Volumes: []string{"k3d-test-images:/k3d/images", "/tmp/data:/data", "k3d-test-cache:/var/cache:ro"},
+			ServerOpts: K3D.ServerOpts{KubeAPI: &K3D.ExposureOpts{
+				Host: "0.0.0.0",
+			}},
+		},
+		{
+			Name:    "k3d-test-server-1",
+			Role:    K3D.ServerRole,
+			Cmd:     []string{"server", "--tls-san", "0.0.0.0", "--disable=traefik"},
+			Env:     []string{"K3S_TOKEN=secret", "K3S_URL=https://k3d-test-server-0:6443", "PATH=/bin"},
+			Volumes: []string{"k3d-test-images:/k3d/images", "/tmp/data:/data"},
+		},
+	}
+
+	agents := []*K3D.Node{
+		{
+			Name:    "k3d-test-agent-0",
+			Role:    K3D.AgentRole,
+			Cmd:     []string{"agent", "--node-label", "tier=worker"},
+			Env:     []string{"K3S_TOKEN=secret", "PATH=/bin", "LOG=debug"},
+			Volumes: []string{"k3d-test-images:/k3d/images"},
+		},
+	}
+
+	return servers, agents
+}
+
+func Test_getNodeFilters(t *testing.T) {
+	servers, agents := getImportTestNodes()
+
+	t.Run("should use wildcard when all nodes of a role match", func(t *testing.T) {
+		expected := []string{"server:*", "agent:0"}
+		assert.Equal(t, expected, getNodeFilters([]string{"k3d-test-server-0", "k3d-test-server-1", "k3d-test-agent-0"}, servers, agents))
+	})
+
+	t.Run("should list the indexes when only few nodes of a role match", func(t *testing.T) {
+		expected := []string{"server:1"}
+		assert.Equal(t, expected, getNodeFilters([]string{"k3d-test-server-1"}, servers, agents))
+	})
+}
+
+func Test_getNodeK3sArgs(t *testing.T) {
+	servers, _ := getImportTestNodes()
+
+	t.Run("should skip the arguments added by k3d", func(t *testing.T) {
+		extraArgs, nodeLabels := getNodeK3sArgs(servers[0])
+		assert.Equal(t, []string{"--disable=traefik"}, extraArgs)
+		assert.Equal(t, []string{"tier=control"}, nodeLabels)
+	})
+
+	t.Run("should pass on the node taints as extra arguments", func(t *testing.T) {
+		agent := &K3D.Node{Cmd: []string{"agent", "--node-taint", "gpu=true:NoSchedule", "--node-label=tier=gpu"}}
+
+		extraArgs, nodeLabels := getNodeK3sArgs(agent)
+		assert.Equal(t, []string{"--node-taint=gpu=true:NoSchedule"}, extraArgs)
+		assert.Equal(t, []string{"tier=gpu"}, nodeLabels)
+	})
+}
+
+func Test_getClusterK3sOptions(t *testing.T) {
+	servers, agents := getImportTestNodes()
+
+	expected := []any{map[string]any{
+		"extra_args": []any{
+			map[string]any{"key": "disable", "value": "traefik", "node_filters": []string{"server:*"}},
+		},
+		"node_labels": []any{
+			map[string]any{"key": "tier", "value": "control", "node_filters": []string{"server:0"}},
+			map[string]any{"key": "tier", "value": "worker", "node_filters": []string{"agent:0"}},
+		},
+	}}
+	assert.Equal(t, expected, getClusterK3sOptions(servers, agents))
+}
+
+func Test_getClusterEnvVars(t *testing.T) {
+	servers, agents := getImportTestNodes()
+
+	t.Run("should skip the environment variables from image and the ones set by k3d", func(t *testing.T) {
+		expected := []any{
+			map[string]any{"key": "LOG", "value": "debug", "node_filters": []string{"server:0", "agent:0"}},
+		}
+		assert.Equal(t, expected, getClusterEnvVars([]string{"PATH=/bin"}, servers, agents))
+	})
+}
+
+func Test_getClusterVolumes(t *testing.T) {
+	servers, agents := getImportTestNodes()
+
+	t.Run("should skip the image volume", func(t *testing.T) {
+		expected := []any{
+			map[string]any{"source": "/tmp/data", "destination": "/data", "mode": "", "type": "bind", "node_filters": []string{"server:*"}},
+			map[string]any{"source": "k3d-test-cache", "destination": "/var/cache", "mode": "ro", "type": "named", "node_filters": []string{"server:0"}},
+		}
+		assert.Equal(t, expected, getClusterVolumes("k3d-test-images", servers, agents))
+	})
+}
+
+func Test_getVolumeMount(t *testing.T) {
+	for volume, expected := range map[string][]string{
+		"/data":                  {"", "/data", ""},
+		"/data:ro":               {"", "/data", "ro"},
+		"/tmp/data:/data":        {"/tmp/data", "/data", ""},
+		"k3d-cache:/var/cache:Z": {"k3d-cache", "/var/cache", "Z"},
+	} {
+		source, destination, mode := getVolumeMount(volume)
+		assert.Equal(t, expected, []string{source, destination, mode}, volume)
+	}
+}
+
+func Test_getClusterKubeAPI(t *testing.T) {
+	t.Run("should read tls_san from the arguments of the server", func(t *testing.T) {
+		server := &K3D.Node{
+			Cmd:        []string{"server", "--tls-san", "k3d.local", "--tls-san=api.example.com", "--tls-san=10.0.0.10"},
+			ServerOpts: K3D.ServerOpts{KubeAPI: &K3D.ExposureOpts{Host: "k3d.local"}},
+		}
+		server.ServerOpts.KubeAPI.Binding.HostIP = "0.0.0.0"
+		server.ServerOpts.KubeAPI.Binding.HostPort = "6443"
+
+		expected := []any{map[string]any{
+			"host": "k3d.local", "host_ip": "0.0.0.0", "host_port": 6443, "tls_san": []string{"api.example.com", "10.0.0.10"},
+		}}
+		assert.Equal(t, expected, getClusterKubeAPI(server))
+
+		extraArgs, _ := getNodeK3sArgs(server)
+		assert.Empty(t, extraArgs)
+	})
+
+	t.Run("should not set host when it defaults to host_ip", func(t *testing.T) {
+		server := &K3D.Node{ServerOpts: K3D.ServerOpts{KubeAPI: &K3D.ExposureOpts{Host: "0.0.0.0"}}}
+		server.ServerOpts.KubeAPI.Binding.HostIP = "0.0.0.0"
+		server.ServerOpts.KubeAPI.Binding.HostPort = "6445"
+
+		expected := []any{map[string]any{"host": "", "host_ip": "0.0.0.0", "host_port": 6445, "tls_san": []string{}}}
+		assert.Equal(t, expected, getClusterKubeAPI(server))
+	})
+
+	t.Run("should set host when it differs from host_ip", func(t *testing.T) {
+		server := &K3D.Node{ServerOpts: K3D.ServerOpts{KubeAPI: &K3D.ExposureOpts{Host: "k3d.local"}}}
+		server.ServerOpts.KubeAPI.Binding.HostIP = "127.0.0.1"
+		server.ServerOpts.KubeAPI.Binding.HostPort = "6443"
+
+		expected := []any{map[string]any{"host": "k3d.local", "host_ip": "127.0.0.1", "host_port": 6443, "tls_san": []string{}}}
+		assert.Equal(t, expected, getClusterKubeAPI(server))
+	})
+}
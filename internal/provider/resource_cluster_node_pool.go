@@ -0,0 +1,240 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"maps"
+	"reflect"
+	"slices"
+
+	dockerunits "github.com/docker/go-units"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	terraformErrors "github.com/nikhilsbhat/terraform-provider-k3d/pkg/errors"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/k3d/cluster"
+	k3dNode "github.com/nikhilsbhat/terraform-provider-k3d/pkg/k3d/node"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/utils"
+	"github.com/rancher/k3d/v5/pkg/config/v1alpha4"
+	"github.com/rancher/k3d/v5/pkg/runtimes"
+	K3D "github.com/rancher/k3d/v5/pkg/types"
+)
+
+// customizeClusterNodePoolsDiff validates that the names of the node pools are unique and their memory limits are valid.
+func customizeClusterNodePoolsDiff(_ context.Context, d *schema.ResourceDiff, _ any) error {
+	names := make(map[string]bool)
+
+	for _, nodePool := range d.Get(utils.TerraformResourceNodePool).([]any) {
+		if nodePool == nil {
+			continue
+		}
+
+		pool := nodePool.(map[string]any)
+		name := utils.String(pool["name"])
+
+		if len(name) != 0 && names[name] {
+			return fmt.Errorf("%s: name '%s' is used by more than one node pool", utils.TerraformResourceNodePool, name)
+		}
+
+		names[name] = true
+
+		if memory := utils.String(pool["memory"]); len(memory) != 0 {
+			if _, err := dockerunits.RAMInBytes(memory); err != nil {
+				return fmt.Errorf("%s '%s': %w", utils.TerraformResourceNodePool, name, terraformErrors.ErrInvalidMemoryLimit)
+			}
+		}
+	}
+
+	return nil
+}
+
+// createClusterNodePools creates the agents of every node pool of the newly created cluster.
+func createClusterNodePools(ctx context.Context, runtime runtimes.Runtime, nodePools []*k3dNode.Config) error {
+	for _, nodePool := range nodePools {
+		if err := nodePool.ScaleNodes(ctx, runtime, nodePool.Count); err != nil {
+			return fmt.Errorf("creating agents of node pool '%s' errored with: %w", nodePool.Pool, err)
+		}
+	}
+
+	return nil
+}
+
+// updateClusterNodePools scales the node pools whose count was changed, without touching the other pools.
+// The agents of a pool are recreated when any of its other settings, or the image of the cluster it defaults to, was changed.
+func updateClusterNodePools(ctx context.Context, d *schema.ResourceData, runtime runtimes.Runtime, clusterName string) diag.Diagnostics {
+	k3dOptions, err := flattenK3DOptions(d.Get(utils.TerraformResourceK3dOptions))
+	if err != nil {
+		return diag.Errorf("fetching %s errored with: %v", utils.TerraformResourceK3dOptions, err)
+	}
+
+	oldImage, newImage := d.GetChange(utils.TerraformResourceImage)
+	oldNodePools, newNodePools := d.GetChange(utils.TerraformResourceNodePool)
+
+	existingPools := make(map[string]*k3dNode.Config)
+	for _, nodePool := range flattenClusterNodePools(oldNodePools, clusterName, utils.String(oldImage), k3dOptions) {
+		existingPools[nodePool.Pool] = nodePool
+	}
+
+	for _, nodePool := range flattenClusterNodePools(newNodePools, clusterName, utils.String(newImage), k3dOptions) {
+		existingPool, ok := existingPools[nodePool.Pool]
+		delete(existingPools, nodePool.Pool)
+
+		if ok && !isNodePoolChanged(existingPool, nodePool) && existingPool.Count == nodePool.Count {
+			continue
+		}
+
+		if ok && isNodePoolChanged(existingPool, nodePool) {
+			log.Printf("settings of node pool '%s' of cluster '%s' were changed, its agents would be recreated", nodePool.Pool, clusterName)
+
+			if err = existingPool.ScaleNodes(ctx, runtime, 0); err != nil {
+				return diag.Errorf("removing agents of node pool '%s' of cluster '%s' errored with: %v", nodePool.Pool, clusterName, err)
+			}
+		}
+
+		log.Printf("scaling node pool '%s' of cluster '%s' to %d", nodePool.Pool, clusterName, nodePool.Count)
+
+		if err = nodePool.ScaleNodes(ctx, runtime, nodePool.Count); err != nil {
+			return diag.Errorf("scaling node pool '%s' of cluster '%s' errored with: %v", nodePool.Pool, clusterName, err)
+		}
+	}
+
+	for _, removedPool := range existingPools {
+		log.Printf("node pool '%s' was removed from cluster '%s', removing its agents", removedPool.Pool, clusterName)
+
+		if err = removedPool.ScaleNodes(ctx, runtime, 0); err != nil {
+			return diag.Errorf("removing agents of node pool '%s' of cluster '%s' errored with: %v", removedPool.Pool, clusterName, err)
+		}
+	}
+
+	return nil
+}
+
+// isNodePoolChanged checks if the settings of the agents of the pool were changed, leaving out the count of the agents.
+func isNodePoolChanged(existing, desired *k3dNode.Config) bool {
+	existingPool, desiredPool := *existing, *desired
+	existingPool.Count, desiredPool.Count = 0, 0
+
+	return !reflect.DeepEqual(existingPool, desiredPool)
+}
+
+// getClusterNodePools sets the count of the node pools to the number of agents the pool has in the cluster.
+func getClusterNodePools(k3dCluster *K3D.Cluster, nodePools []any) []any {
+	nodes := make(map[string][]string)
+
+	for _, node := range k3dCluster.Nodes {
+		if pool, ok := node.RuntimeLabels[utils.TerraformNodePoolLabel]; ok && node.Role == K3D.AgentRole {
+			nodes[pool] = append(nodes[pool], node.Name)
+		}
+	}
+
+	clusterNodePools := make([]any, 0, len(nodePools))
+
+	for _, nodePool := range nodePools {
+		if nodePool == nil {
+			continue
+		}
+
+		pool := maps.Clone(nodePool.(map[string]any))
+		name := utils.String(pool["name"])
+		pool["count"] = len(k3dNode.IndexedNodes(getClusterNodePoolPrefix(k3dCluster.Name, name), nodes[name]))
+
+		clusterNodePools = append(clusterNodePools, pool)
+	}
+
+	return clusterNodePools
+}
+
+// flattenClusterNodePools returns the agents of every node pool as node config, where the count is the desired number of agents.
+// The agents of a pool run the image of the cluster, unless the pool sets its own.
+func flattenClusterNodePools(nodePools any, clusterName, image string, k3dOptions v1alpha4.SimpleConfigOptionsK3d) []*k3dNode.Config {
+	pools := make([]*k3dNode.Config, 0)
+
+	for _, nodePool := range nodePools.([]any) {
+		if nodePool == nil {
+			continue
+		}
+
+		pool := nodePool.(map[string]any)
+		name := utils.String(pool["name"])
+
+		nodeConfig := &k3dNode.Config{
+			Name:                 []string{getClusterNodePoolPrefix(clusterName, name)},
+			ClusterAssociated:    clusterName,
+			Role:                 string(K3D.AgentRole),
+			Count:                utils.Int(pool["count"]),
+			Image:                utils.String(pool["image"]),
+			Memory:               utils.String(pool["memory"]),
+			Pool:                 name,
+			K3sNodeLabels:        flattenStringMap(pool["labels"]),
+			K3sNodeTaints:        utils.GetSlice(pool["taints"].([]any)),
+			EnvironmentVariables: flattenNodeEnv(pool["env"]),
+			Volumes:              flattenNodeVolumes(pool["volumes"].([]any)),
+			Wait:                 k3dOptions.Wait,
+			Timeout:              k3dOptions.Timeout,
+		}
+
+		if len(nodeConfig.Image) == 0 {
+			nodeConfig.Image = image
+		}
+
+		pools = append(pools, nodeConfig)
+	}
+
+	return pools
+}
+
+// getClusterNodePoolsCount returns the total number of agents across the node pools.
+func getClusterNodePoolsCount(nodePools []*k3dNode.Config) int {
+	count := 0
+	for _, nodePool := range nodePools {
+		count += nodePool.Count
+	}
+
+	return count
+}
+
+func flattenStringMap(value any) map[string]string {
+	values := make(map[string]string)
+	for key, val := range value.(map[string]any) {
+		values[key] = utils.String(val)
+	}
+
+	return values
+}
+
+// flattenNodeEnv returns the environment variables in the format KEY=VALUE, sorted by the key for a stable order.
+func flattenNodeEnv(value any) []string {
+	env := flattenStringMap(value)
+
+	envs := make([]string, 0, len(env))
+	for _, key := range slices.Sorted(maps.Keys(env)) {
+		envs = append(envs, fmt.Sprintf("%s=%s", key, env[key]))
+	}
+
+	return envs
+}
+
+func flattenNodeVolumes(volumes []any) []string {
+	nodeVolumes := make([]string, 0, len(volumes))
+
+	for _, vol := range volumes {
+		if vol == nil {
+			continue
+		}
+
+		v := vol.(map[string]any)
+		volume := &cluster.Volume{
+			Source:      utils.String(v["source"]),
+			Destination: utils.String(v["destination"]),
+			Mode:        utils.String(v["mode"]),
+		}
+
+		nodeVolumes = append(nodeVolumes, volume.GetVolumeWithNodeFilters().Volume)
+	}
+
+	return nodeVolumes
+}
+
+func getClusterNodePoolPrefix(clusterName, nodePool string) string {
+	return fmt.Sprintf("%s-%s-%s", K3D.DefaultObjectNamePrefix, clusterName, nodePool)
+}
@@ -3,6 +3,7 @@ package provider
 import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/k3d/cluster"
 )
 
 func resourceClusterSchema() map[string]*schema.Schema {
@@ -77,7 +78,41 @@ func resourceClusterRegistriesSchema() map[string]*schema.Schema {
 			Type:        schema.TypeBool,
 			Optional:    true,
 			Computed:    false,
-			Description: "creates a default registry to be used with the cluster",
+			Description: "creates a registry to be used with the cluster, which would be deleted along with the cluster",
+		},
+		"name": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Computed:    false,
+			Description: "name of the registry to be created (defaults to `k3d-<cluster>-registry`)",
+		},
+		"host": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Computed:     false,
+			Description:  "host address on which the port of the registry to be created is exposed (defaults to `0.0.0.0`)",
+			ValidateFunc: validation.IsIPAddress,
+		},
+		"host_port": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Computed:     false,
+			Description:  "host port on which the registry to be created is exposed (random port is chosen if not set)",
+			ValidateFunc: validation.IsPortNumber,
+		},
+		"image": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Computed:    false,
+			Description: "image to be used for the registry to be created (defaults to `docker.io/library/registry:2`)",
+		},
+		"proxy": {
+			Type:        schema.TypeMap,
+			Optional:    true,
+			Computed:    false,
+			Sensitive:   true,
+			Description: "configures the registry to be created as pull through cache, supported keys are `remoteURL`, `username` and `password`",
+			Elem:        &schema.Schema{Type: schema.TypeString},
 		},
 		"use": {
 			Type:        schema.TypeList,
@@ -92,6 +127,116 @@ func resourceClusterRegistriesSchema() map[string]*schema.Schema {
 			Computed:    false,
 			Description: "define contents of the `registries.yaml` file (or reference a file); same as `--registry-config /path/to/config.yaml`",
 		},
+		"mirrors": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Computed:    false,
+			Description: "mirrors to be rendered into the `registries.yaml` of k3s, cannot be used along with `config`",
+			Elem: &schema.Resource{
+				Schema: resourceClusterRegistryMirrorsSchema(),
+			},
+		},
+		"configs": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Computed:    false,
+			Description: "auth and TLS configs of registries to be rendered into the `registries.yaml` of k3s, cannot be used along with `config`",
+			Elem: &schema.Resource{
+				Schema: resourceClusterRegistryConfigsSchema(),
+			},
+		},
+	}
+}
+
+func resourceClusterRegistryMirrorsSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"registry": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "name of the registry to be mirrored, ex: `docker.io`",
+		},
+		"endpoints": {
+			Type:        schema.TypeList,
+			Required:    true,
+			Description: "endpoints of the mirror, they are tried one by one until a working one is found",
+			Elem: &schema.Schema{
+				Type:         schema.TypeString,
+				ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+			},
+		},
+	}
+}
+
+func resourceClusterRegistryConfigsSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"registry": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "FQDN or IP of the registry to be configured",
+		},
+		"auth": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "credentials to authenticate with the registry",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"username": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Description: "username to login to the registry",
+					},
+					"password": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Sensitive:   true,
+						Description: "password to login to the registry",
+					},
+					"auth": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Sensitive:   true,
+						Description: "base64 encoded string of `username:password` to login to the registry",
+					},
+					"identity_token": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Sensitive:   true,
+						Description: "token to authenticate the user and get an access token for the registry",
+					},
+				},
+			},
+		},
+		"tls": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "TLS config to be used while communicating with the registry",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"ca_file": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Description: "path to the CA file on the nodes",
+					},
+					"cert_file": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Description: "path to the client certificate file on the nodes",
+					},
+					"key_file": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Description: "path to the client key file on the nodes",
+					},
+					"insecure_skip_verify": {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Description: "skips verifying the certificate of the registry when set to true",
+					},
+				},
+			},
+		},
 	}
 }
 
@@ -160,13 +305,210 @@ func resourceClusterRuntimeSchema() map[string]*schema.Schema {
 	}
 }
 
+func resourceClusterKubeAPISchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"host": {
+			Description: "Important for the `server` setting in the kubeconfig.",
+			ForceNew:    true,
+			Optional:    true,
+			Type:        schema.TypeString,
+		},
+		"host_ip": {
+			Description:  "Where the Kubernetes API will be listening on.",
+			ForceNew:     true,
+			Optional:     true,
+			Type:         schema.TypeString,
+			ValidateFunc: validation.IsIPAddress,
+		},
+		"host_port": {
+			Description: "Specify the Kubernetes API server port exposed on the LoadBalancer, " +
+				"a free port is picked when not set and the port it is actually published to is stored here.",
+			ForceNew:     true,
+			Optional:     true,
+			Computed:     true,
+			Type:         schema.TypeInt,
+			ValidateFunc: validation.IsPortNumber,
+		},
+		"tls_san": {
+			Description: "Additional hostnames or IPs to be added to the certificate of the Kubernetes API, " +
+				"so that it could be reached by those as well, same as `--k3s-arg --tls-san=<SAN>@server:*`.",
+			ForceNew: true,
+			Optional: true,
+			Type:     schema.TypeList,
+			Elem: &schema.Schema{
+				Type:         schema.TypeString,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+			},
+		},
+	}
+}
+
+// resourceClusterKubeAPIHash hashes the kube_api leaving out host_port, which is computed when not set.
+func resourceClusterKubeAPIHash(kubeAPI any) int {
+	kubeAPISchema := resourceClusterKubeAPISchema()
+	delete(kubeAPISchema, "host_port")
+
+	return schema.HashResource(&schema.Resource{Schema: kubeAPISchema})(kubeAPI)
+}
+
+func resourceClusterK3sOptionsSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"extra_args": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Computed:    false,
+			Description: "additional arguments passed to the `k3s server|agent` command; same as `--k3s-arg`",
+			Elem: &schema.Resource{
+				Schema: resourceClusterEnvsAndLabelsSchema(),
+			},
+		},
+		"node_labels": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Computed:    false,
+			Description: "same as `--k3s-node-label 'foo=bar@agent:1'` -> this results in a Kubernetes node label",
+			Elem: &schema.Resource{
+				Schema: resourceClusterEnvsAndLabelsSchema(),
+			},
+		},
+	}
+}
+
+func resourceClusterK3sSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"disable": {
+			Type:        schema.TypeSet,
+			Optional:    true,
+			ForceNew:    true,
+			Description: "packaged components of k3s to be disabled, same as `--disable` of k3s",
+			Elem: &schema.Schema{
+				Type:         schema.TypeString,
+				ValidateFunc: validation.StringInSlice(cluster.K3sComponents, false),
+			},
+		},
+		"flannel_backend": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ForceNew:     true,
+			Description:  "backend of flannel, same as `--flannel-backend` of k3s",
+			ValidateFunc: validation.StringInSlice(cluster.FlannelBackends, false),
+		},
+		"cluster_cidr": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ForceNew:     true,
+			Description:  "network CIDR to use for pod IPs, same as `--cluster-cidr` of k3s",
+			ValidateFunc: validation.IsCIDR,
+		},
+		"service_cidr": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ForceNew:     true,
+			Description:  "network CIDR to use for service IPs, same as `--service-cidr` of k3s",
+			ValidateFunc: validation.IsCIDR,
+		},
+		"cluster_dns": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ForceNew:     true,
+			Description:  "IP address of the coredns service, which should be in the range of service_cidr, same as `--cluster-dns` of k3s",
+			ValidateFunc: validation.IsIPAddress,
+		},
+		"cluster_domain": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ForceNew:     true,
+			Description:  "domain of the cluster, same as `--cluster-domain` of k3s",
+			ValidateFunc: validation.StringIsNotWhiteSpace,
+		},
+	}
+}
+
+func resourceClusterNodePoolSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:             schema.TypeString,
+			Required:         true,
+			Description:      "Name of the pool, the agents of the pool are named `k3d-<cluster>-<name>-<index>`",
+			ValidateDiagFunc: validateNodePoolName,
+		},
+		"count": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Default:      1,
+			Description:  "Number of agents in the pool",
+			ValidateFunc: validation.IntAtLeast(0),
+		},
+		"image": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Image of the agents of the pool, defaults to the image of the cluster",
+		},
+		"memory": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Memory limit imposed on the agents of the pool [From docker]",
+		},
+		"labels": {
+			Type:        schema.TypeMap,
+			Optional:    true,
+			Description: "K3s node labels of the agents of the pool, same as `--node-label` of k3s",
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+		"taints": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "K3s node taints of the agents of the pool in the format `key[=value]:effect`, same as `--node-taint` of k3s",
+			Elem: &schema.Schema{
+				Type:             schema.TypeString,
+				ValidateDiagFunc: validateNodeTaint,
+			},
+		},
+		"env": {
+			Type:        schema.TypeMap,
+			Optional:    true,
+			Description: "Environment variables to be added to the agents of the pool",
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+		"volumes": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "Mount volumes into the agents of the pool",
+			Elem: &schema.Resource{
+				Schema: resourceNodeVolumeSchema(),
+			},
+		},
+	}
+}
+
+func resourceNodeVolumeSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"source": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Absolute path on the host or name of the volume of the runtime",
+		},
+		"destination": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "Destination path for the volume",
+		},
+		"mode": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Description:  "Mode of the mount, one of `ro`, `rw`, `z` or `Z`",
+			ValidateFunc: validation.StringInSlice([]string{"ro", "rw", "z", "Z"}, false),
+		},
+	}
+}
+
 func resourceClusterVolumeSchema() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
 		"source": {
 			Type:        schema.TypeString,
 			ForceNew:    true,
 			Optional:    true,
-			Description: "Source path of volume mount",
+			Description: "Absolute path on the host for `bind` volumes or name of the volume for `named` volumes, ex: `/host/foo` for `--volume /host/foo:/node/bar`",
 		},
 		"destination": {
 			Type:        schema.TypeString,
@@ -174,6 +516,28 @@ func resourceClusterVolumeSchema() map[string]*schema.Schema {
 			Required:    true,
 			Description: "Destination path for the volume",
 		},
+		"mode": {
+			Type:         schema.TypeString,
+			ForceNew:     true,
+			Optional:     true,
+			Description:  "Mode of the mount, one of `ro`, `rw`, `z` or `Z`",
+			ValidateFunc: validation.StringInSlice([]string{"ro", "rw", "z", "Z"}, false),
+		},
+		"type": {
+			Type:     schema.TypeString,
+			ForceNew: true,
+			Optional: true,
+			Default:  cluster.VolumeTypeBind,
+			Description: "Type of the volume, `bind` mounts a path of the host, `named` mounts a volume of the runtime " +
+				"which is created along with the cluster when it does not exist",
+			ValidateFunc: validation.StringInSlice([]string{cluster.VolumeTypeBind, cluster.VolumeTypeNamed}, false),
+		},
+		"retain": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Retain the `named` volume created along with the cluster when the cluster is destroyed",
+		},
 		"node_filters": {
 			Type:     schema.TypeList,
 			ForceNew: true,
@@ -183,6 +547,14 @@ func resourceClusterVolumeSchema() map[string]*schema.Schema {
 	}
 }
 
+// resourceClusterVolumeHash hashes the volume leaving out retain, so that it could be changed without replacing the cluster.
+func resourceClusterVolumeHash(volume any) int {
+	volumeSchema := resourceClusterVolumeSchema()
+	delete(volumeSchema, "retain")
+
+	return schema.HashResource(&schema.Resource{Schema: volumeSchema})(volume)
+}
+
 func resourceClusterK3dOptionsSchema() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
 		"wait": {
@@ -233,16 +605,18 @@ func resourceClusterPortsConfig() map[string]*schema.Schema {
 			Optional: true,
 		},
 		"host_port": {
-			Type:         schema.TypeInt,
-			ForceNew:     true,
-			Optional:     true,
-			ValidateFunc: validation.IsPortNumber,
+			Type:             schema.TypeString,
+			ForceNew:         true,
+			Optional:         true,
+			Description:      "Port or range of ports of the host, ex: `8080` or `30000-30010`, the runtime picks the port when not set",
+			ValidateDiagFunc: validateHostPortRange,
 		},
 		"container_port": {
-			Type:         schema.TypeInt,
-			ForceNew:     true,
-			Required:     true,
-			ValidateFunc: validation.IsPortNumber,
+			Type:             schema.TypeString,
+			ForceNew:         true,
+			Required:         true,
+			Description:      "Port or range of ports of the node containers, ex: `80` or `30000-30010`",
+			ValidateDiagFunc: validatePortRange,
 		},
 		"protocol": {
 			Type:         schema.TypeString,
@@ -256,9 +630,23 @@ func resourceClusterPortsConfig() map[string]*schema.Schema {
 			Optional: true,
 			Elem:     &schema.Schema{Type: schema.TypeString},
 		},
+		"published_host_ports": {
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: "Host ports the container ports are actually published to by the runtime, in the order of the container ports",
+			Elem:        &schema.Schema{Type: schema.TypeInt},
+		},
 	}
 }
 
+// resourceClusterPortHash hashes the port leaving out published_host_ports, which is known only once the port is published.
+func resourceClusterPortHash(port any) int {
+	portSchema := resourceClusterPortsConfig()
+	delete(portSchema, "published_host_ports")
+
+	return schema.HashResource(&schema.Resource{Schema: portSchema})(port)
+}
+
 func resourceHostAliasesConfig() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
 		"ip": {
@@ -295,5 +683,219 @@ func resourceKubeconfigConfig() map[string]*schema.Schema {
 			Default:     false,
 			Computed:    false,
 		},
+		"output_path": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Computed:    false,
+			Description: "Path to which the kubeconfig of the cluster is written with `0600` permissions, the file is removed when the cluster is destroyed",
+		},
+		"context_name": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: false,
+			Description: "Go template of the name of the context in kubeconfig, in which `.Name` refers to the name of the cluster " +
+				"(defaults to `k3d-<cluster>`)",
+			ValidateDiagFunc: validateContextName,
+		},
+		"server_override": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Computed:     false,
+			Description:  "URL of the kube API to be set in kubeconfig instead of the one set by k3d, ex: `https://k3d.local:6443`",
+			ValidateFunc: validation.IsURLWithHTTPS,
+		},
+	}
+}
+
+func resourceClusterCreatedRegistrySchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "name of the registry created along with the cluster",
+		},
+		"host": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "host address on which the registry is exposed",
+		},
+		"host_port": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "host port on which the registry is exposed",
+		},
+	}
+}
+
+func resourceClusterNodesSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "name of the node",
+		},
+		"role": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "role of the node, ex: server, agent, loadbalancer or registry",
+		},
+		"ip": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "IP of the node container on the cluster network",
+		},
+		"state": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "state of the node container, ex: running or exited",
+		},
+		"image": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "image the node was created from",
+		},
+		"memory": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "memory limit of the node",
+		},
+		"ports": {
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: "ports of the node published to the host",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"container_port": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "port of the node container along with protocol, ex: 6443/tcp",
+					},
+					"host_ip": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "host address to which the port is published",
+					},
+					"host_port": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "host port to which the port is published",
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceClusterWaitForSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"nodes_ready": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     true,
+			Description: "waits for all the nodes of the cluster to register and be Ready",
+		},
+		"nodes_timeout": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "5m",
+			Description:  "maximum duration to wait for the nodes to be Ready",
+			ValidateFunc: validateDuration,
+		},
+		"interval": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "5s",
+			Description:  "interval at which the readiness is checked",
+			ValidateFunc: validateDuration,
+		},
+		"deployment": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "deployment to be waited for until it is Available",
+			Elem: &schema.Resource{
+				Schema: resourceClusterWaitForWorkloadSchema(),
+			},
+		},
+		"daemonset": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "daemonset to be waited for until all of its pods are Available",
+			Elem: &schema.Resource{
+				Schema: resourceClusterWaitForWorkloadSchema(),
+			},
+		},
+	}
+}
+
+func resourceClusterWaitForWorkloadSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"namespace": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Default:     "kube-system",
+			Description: "namespace of the workload",
+		},
+		"name": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "name of the workload, ex: coredns",
+		},
+		"timeout": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "5m",
+			Description:  "maximum duration to wait for the workload to be Available",
+			ValidateFunc: validateDuration,
+		},
+	}
+}
+
+func resourceClusterAddonsSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "name of the addon, the manifest is written to `addon-<name>.yaml`",
+		},
+		"manifest": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "raw manifest of the addon, cannot be used along with `helm_chart`",
+		},
+		"helm_chart": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "HelmChart to be deployed by the helm controller of k3s, cannot be used along with `manifest`",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"chart": {
+						Type:        schema.TypeString,
+						Required:    true,
+						Description: "name of the chart or URL of the chart archive",
+					},
+					"repo": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Description: "URL of the repository of the chart",
+					},
+					"version": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Description: "version of the chart",
+					},
+					"target_namespace": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Description: "namespace to which the chart is installed",
+					},
+					"values": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Description: "values of the chart in YAML",
+					},
+				},
+			},
+		},
 	}
 }
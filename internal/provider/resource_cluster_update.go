@@ -0,0 +1,281 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/client"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/k3d/cluster"
+	k3dNode "github.com/nikhilsbhat/terraform-provider-k3d/pkg/k3d/node"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/utils"
+	k3dClient "github.com/rancher/k3d/v5/pkg/client"
+	"github.com/rancher/k3d/v5/pkg/config/v1alpha4"
+	"github.com/rancher/k3d/v5/pkg/runtimes"
+	K3D "github.com/rancher/k3d/v5/pkg/types"
+)
+
+const clusterUpgradeInterval = 5 * time.Second
+
+func resourceClusterUpdate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	defaultConfig := meta.(*client.Config)
+
+	clusterName := utils.String(d.Get(utils.TerraformResourceName))
+
+	if d.HasChange(utils.TerraformResourceKubeConfig) {
+		if diags := updateClusterKubeConfig(ctx, d, defaultConfig.K3DRuntime, clusterName); diags != nil {
+			return diags
+		}
+	}
+
+	if d.HasChange(utils.TerraformAddonsHash) {
+		if diags := updateClusterAddons(ctx, d, defaultConfig.K3DRuntime, clusterName); diags != nil {
+			return diags
+		}
+	}
+
+	var diags diag.Diagnostics
+
+	// nodes are upgraded before scaling, so that the nodes added copy the upgraded ones.
+	if d.HasChange(utils.TerraformResourceImage) {
+		if diags = upgradeClusterImage(ctx, d, defaultConfig.K3DRuntime, clusterName); diags.HasError() {
+			return diags
+		}
+	}
+
+	if d.HasChanges(utils.TerraformResourceNodePool, utils.TerraformResourceImage) {
+		if poolDiags := updateClusterNodePools(ctx, d, defaultConfig.K3DRuntime, clusterName); poolDiags != nil {
+			// the earlier node pools are left in the state, so that the pools failed to be updated are retried on next apply.
+			d.Partial(true)
+
+			return append(diags, poolDiags...)
+		}
+	}
+
+	if d.HasChanges(utils.TerraformResourceServersCount, utils.TerraformResourceAgentsCount) {
+		if scaleDiags := scaleClusterNodes(ctx, d, defaultConfig.K3DRuntime, clusterName); scaleDiags != nil {
+			return append(diags, scaleDiags...)
+		}
+	}
+
+	// addons are written to the containers of the servers rather than mounted, so they are written again to the servers replaced or added.
+	if d.HasChanges(utils.TerraformResourceImage, utils.TerraformResourceServersCount) {
+		if addonDiags := writeClusterAddons(ctx, d, defaultConfig.K3DRuntime, clusterName); addonDiags != nil {
+			return append(diags, addonDiags...)
+		}
+	}
+
+	// wait_for is checked again when it is changed or when the nodes of the cluster are changed, as it would be after creating the cluster.
+	if d.HasChanges(utils.TerraformWaitFor, utils.TerraformResourceServersCount, utils.TerraformResourceAgentsCount,
+		utils.TerraformResourceNodePool, utils.TerraformResourceImage) {
+		if waitDiags := waitForCluster(ctx, d, defaultConfig.K3DRuntime, clusterName, getClusterExpectedNodes(d, clusterName)); waitDiags != nil {
+			// the earlier wait_for is left in the state, so that the cluster is waited for again on next apply.
+			d.Partial(true)
+
+			return append(diags, waitDiags...)
+		}
+	}
+
+	return append(diags, resourceClusterRead(ctx, d, meta)...)
+}
+
+// scaleClusterNodes scales the servers and the agents created along with the cluster to servers_count and agents_count.
+func scaleClusterNodes(ctx context.Context, d *schema.ResourceData, runtime runtimes.Runtime, clusterName string) diag.Diagnostics {
+	clusterCfg := cluster.Config{}
+
+	clusters, err := clusterCfg.GetClusters(ctx, runtime, []string{clusterName})
+	if err != nil {
+		return diag.Errorf("fetching cluster '%s' errored with %v", clusterName, err)
+	}
+
+	if len(clusters) == 0 {
+		return diag.Errorf("cluster '%s' not found to scale", clusterName)
+	}
+
+	k3dOptions, err := flattenK3DOptions(d.Get(utils.TerraformResourceK3dOptions))
+	if err != nil {
+		return diag.Errorf("fetching %s errored with: %v", utils.TerraformResourceK3dOptions, err)
+	}
+
+	servers := getClusterNodesToScale(clusterName, K3D.ServerRole, k3dOptions.Wait, k3dOptions.Timeout)
+	agents := getClusterNodesToScale(clusterName, K3D.AgentRole, k3dOptions.Wait, k3dOptions.Timeout)
+
+	desiredServers := utils.Int(d.Get(utils.TerraformResourceServersCount))
+	desiredAgents := utils.Int(d.Get(utils.TerraformResourceAgentsCount))
+
+	if d.HasChange(utils.TerraformResourceServersCount) {
+		// the servers removed are removed from embedded etcd through the kubernetes API before their containers are deleted.
+		if oldServers, _ := d.GetChange(utils.TerraformResourceServersCount); desiredServers < utils.Int(oldServers) {
+			if servers.KubeClient, err = getClusterKubeClient(ctx, runtime, clusterName); err != nil {
+				return diag.Errorf("scaling servers of cluster '%s' errored with: %v", clusterName, err)
+			}
+		}
+
+		if err = validateServerScaling(ctx, runtime, servers, clusters[0].ServersCount, desiredServers); err != nil {
+			return diag.Errorf("scaling servers of cluster '%s' from %d to %d errored with: %v",
+				clusterName, clusters[0].ServersCount, desiredServers, err)
+		}
+
+		log.Printf("scaling servers of cluster '%s' to %d", clusterName, desiredServers)
+
+		if err = servers.ScaleNodes(ctx, runtime, desiredServers); err != nil {
+			return diag.Errorf("scaling servers of cluster '%s' errored with: %v", clusterName, err)
+		}
+	}
+
+	if d.HasChange(utils.TerraformResourceAgentsCount) {
+		log.Printf("scaling agents of cluster '%s' to %d", clusterName, desiredAgents)
+
+		if err = agents.ScaleNodes(ctx, runtime, desiredAgents); err != nil {
+			return diag.Errorf("scaling agents of cluster '%s' errored with: %v", clusterName, err)
+		}
+	}
+
+	return nil
+}
+
+// getClusterExpectedNodes returns the count of the nodes the cluster is expected to have as per servers_count, agents_count and node_pool.
+func getClusterExpectedNodes(d *schema.ResourceData, clusterName string) int {
+	nodePools := flattenClusterNodePools(d.Get(utils.TerraformResourceNodePool), clusterName,
+		utils.String(d.Get(utils.TerraformResourceImage)), v1alpha4.SimpleConfigOptionsK3d{})
+
+	return utils.Int(d.Get(utils.TerraformResourceServersCount)) + utils.Int(d.Get(utils.TerraformResourceAgentsCount)) +
+		getClusterNodePoolsCount(nodePools)
+}
+
+// upgradeClusterImage upgrades the nodes created along with the cluster to the image, one at a time.
+// The nodes upgraded are reported as warning, and the image is left unchanged in the state when any of the nodes fails to be upgraded.
+func upgradeClusterImage(ctx context.Context, d *schema.ResourceData, runtime runtimes.Runtime, clusterName string) diag.Diagnostics {
+	image := utils.String(d.Get(utils.TerraformResourceImage))
+
+	k3dCluster, err := k3dClient.ClusterGet(ctx, runtime, &K3D.Cluster{Name: clusterName})
+	if err != nil {
+		return diag.Errorf("fetching cluster '%s' to upgrade errored with: %v", clusterName, err)
+	}
+
+	kubeConfig, err := k3dClient.KubeconfigGet(ctx, runtime, k3dCluster)
+	if err != nil {
+		return diag.Errorf("fetching kubeconfig of cluster '%s' errored with: %v", clusterName, err)
+	}
+
+	kubeClient, err := cluster.NewKubeClient(kubeConfig)
+	if err != nil {
+		return diag.Errorf("creating kubernetes client for cluster '%s' errored with: %v", clusterName, err)
+	}
+
+	k3dOptions, err := flattenK3DOptions(d.Get(utils.TerraformResourceK3dOptions))
+	if err != nil {
+		return diag.Errorf("fetching %s errored with: %v", utils.TerraformResourceK3dOptions, err)
+	}
+
+	upgradeCfg := &k3dNode.UpgradeConfig{
+		Image:      image,
+		KubeClient: kubeClient,
+		Timeout:    k3dOptions.Timeout,
+		Interval:   clusterUpgradeInterval,
+	}
+
+	if upgradeCfg.Timeout == 0 {
+		upgradeCfg.Timeout = utils.TerraformTimeOut5 * time.Minute
+	}
+
+	nodes := append(getClusterNodesByRole(k3dCluster, K3D.ServerRole), getClusterNodesByRole(k3dCluster, K3D.AgentRole)...)
+
+	upgraded, err := upgradeCfg.UpgradeNodes(ctx, runtime, nodes)
+	if err != nil {
+		d.Partial(true)
+
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("upgrading cluster '%s' to image '%s' errored", clusterName, image),
+			Detail: fmt.Sprintf("%v\nnodes upgraded before the failure: [%s], the rest of the nodes are left running the earlier image",
+				err, strings.Join(upgraded, ", ")),
+		}}
+	}
+
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("upgraded %d of %d nodes of cluster '%s' to image '%s'", len(upgraded), len(nodes), clusterName, image),
+		Detail:   fmt.Sprintf("nodes upgraded in order: [%s], the rest were already running the image", strings.Join(upgraded, ", ")),
+	}}
+}
+
+// updateClusterKubeConfig removes the kubeconfig written as per the earlier kube_config and writes it as per the current one.
+func updateClusterKubeConfig(ctx context.Context, d *schema.ResourceData, runtime runtimes.Runtime, clusterName string) diag.Diagnostics {
+	oldKubeConfig, newKubeConfig := d.GetChange(utils.TerraformResourceKubeConfig)
+	k3dCluster := &K3D.Cluster{Name: clusterName}
+
+	oldKubeConfigOptions := getClusterKubeConfigOptions(d, oldKubeConfig)
+	newKubeConfigOptions := getClusterKubeConfigOptions(d, newKubeConfig)
+
+	// output_path is unset in the state when the file has drifted, the file has to be retained if the path is unchanged.
+	if oldKubeConfigOptions.OutputPath == newKubeConfigOptions.OutputPath {
+		oldKubeConfigOptions.OutputPath = ""
+	}
+
+	if err := oldKubeConfigOptions.Remove(ctx, k3dCluster); err != nil {
+		return diag.Errorf("removing kubeconfig of cluster '%s' errored with: %v", clusterName, err)
+	}
+
+	if err := newKubeConfigOptions.Write(ctx, runtime, k3dCluster); err != nil {
+		return diag.Errorf("writing kubeconfig of cluster '%s' errored with: %v", clusterName, err)
+	}
+
+	return nil
+}
+
+func getClusterNodesToScale(clusterName string, role K3D.Role, wait bool, timeout time.Duration) *k3dNode.Config {
+	return &k3dNode.Config{
+		Name:              []string{getClusterNodePrefix(clusterName, role)},
+		ClusterAssociated: clusterName,
+		Role:              string(role),
+		Wait:              wait,
+		Timeout:           timeout,
+	}
+}
+
+// validateServerScaling translates the desired count of the servers managed by terraform to the count of
+// all servers in the cluster, before validating it against the etcd quorum.
+// The etcd members are counted through the kubernetes API when the servers are to be removed.
+func validateServerScaling(ctx context.Context, runtime runtimes.Runtime, servers *k3dNode.Config, current, desired int) error {
+	managedServers, err := servers.GetIndexedNodes(ctx, runtime)
+	if err != nil {
+		return err
+	}
+
+	embeddedEtcd, err := cluster.HasEmbeddedEtcd(ctx, runtime, servers.ClusterAssociated)
+	if err != nil {
+		return err
+	}
+
+	members := current
+	if servers.KubeClient != nil {
+		if members, err = cluster.GetEtcdMembers(ctx, servers.KubeClient); err != nil {
+			return fmt.Errorf("fetching etcd members of cluster '%s' errored with: %w", servers.ClusterAssociated, err)
+		}
+	}
+
+	return cluster.ValidateServerScaling(members, current, current+desired-len(managedServers), embeddedEtcd)
+}
+
+func getClusterNodePrefix(clusterName string, role K3D.Role) string {
+	return fmt.Sprintf("%s-%s-%s", K3D.DefaultObjectNamePrefix, clusterName, role)
+}
+
+// getClusterNodesCount counts the nodes of the specified role that were created along with the cluster,
+// nodes joined to the cluster by other means (ex: k3d_node) are not considered.
+func getClusterNodesCount(k3dCluster *K3D.Cluster, role K3D.Role) int {
+	nodes := make([]string, 0)
+
+	for _, node := range k3dCluster.Nodes {
+		if node.Role == role {
+			nodes = append(nodes, node.Name)
+		}
+	}
+
+	return len(k3dNode.IndexedNodes(getClusterNodePrefix(k3dCluster.Name, role), nodes))
+}
@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	terraformErrors "github.com/nikhilsbhat/terraform-provider-k3d/pkg/errors"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/k3d/cluster"
+	k3dClient "github.com/rancher/k3d/v5/pkg/client"
+	"github.com/rancher/k3d/v5/pkg/runtimes"
+	K3D "github.com/rancher/k3d/v5/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// removeIfNotFound clears the ID of the resource when err says that the object it manages is gone from the runtime,
+// so that terraform plans to recreate it instead of failing every refresh until the resource is removed from state.
+func removeIfNotFound(d *schema.ResourceData, err error) bool {
+	if !terraformErrors.IsNotFound(err) {
+		return false
+	}
+
+	log.Printf("%v, removing it from state", err)
+	d.SetId("")
+
+	return true
+}
+
+// validateDuration validates that the value is a duration parsable by time.ParseDuration, ex: 90s or 5m.
+func validateDuration(value any, key string) ([]string, []error) {
+	if _, err := time.ParseDuration(value.(string)); err != nil {
+		return nil, []error{fmt.Errorf("%s should be a valid duration, ex: 90s or 5m: %w", key, err)}
+	}
+
+	return nil, nil
+}
+
+// getClusterKubeClient returns the client of the kubernetes API of the cluster, built from the kubeconfig read from its servers.
+func getClusterKubeClient(ctx context.Context, runtime runtimes.Runtime, clusterName string) (kubernetes.Interface, error) {
+	kubeConfig, err := k3dClient.KubeconfigGet(ctx, runtime, &K3D.Cluster{Name: clusterName})
+	if err != nil {
+		return nil, fmt.Errorf("fetching kubeconfig of cluster '%s' errored with: %w", clusterName, err)
+	}
+
+	kubeClient, err := cluster.NewKubeClient(kubeConfig)
+	if err != nil {
+		return nil, fmt.Errorf("creating kubernetes client for cluster '%s' errored with: %w", clusterName, err)
+	}
+
+	return kubeClient, nil
+}
@@ -124,9 +124,11 @@ func resourceLoadImageRead(ctx context.Context, d *schema.ResourceData, meta any
 
 	imagesToStore, err := imageCfg.List(ctx, defaultConfig.K3DRuntime)
 	if err != nil {
-		d.SetId("")
+		if removeIfNotFound(d, err) {
+			return nil
+		}
 
-		diag.Errorf("an error occurred while fetching images to be stored")
+		return diag.Errorf("an error occurred while fetching images to be stored: %v", err)
 	}
 
 	flattenedImagesToStore, err := utils2.MapSlice(imagesToStore)
@@ -0,0 +1,143 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/client"
+	k3dNetwork "github.com/nikhilsbhat/terraform-provider-k3d/pkg/k3d/network"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/utils"
+)
+
+func resourceNetwork() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceNetworkCreate,
+		ReadContext:   resourceNetworkRead,
+		DeleteContext: resourceNetworkDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "name of the network to be created, which the clusters and registries could refer to",
+			},
+			"subnet": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsCIDR,
+				Description:  "subnet of the network in CIDR format, picked by the runtime when not set",
+			},
+			"gateway": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				RequiredWith: []string{"subnet"},
+				ValidateFunc: validation.IsIPAddress,
+				Description:  "gateway of the network, picked by the runtime from the subnet when not set",
+			},
+			"ip_range": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				RequiredWith: []string{"subnet"},
+				ValidateFunc: validation.IsCIDR,
+				Description:  "range within the subnet, in CIDR format, to allocate the container IPs from",
+			},
+			"labels": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "labels to be set on the network",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"network_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "ID of the network in the runtime",
+			},
+		},
+	}
+}
+
+func resourceNetworkCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	defaultConfig := meta.(*client.Config)
+
+	labels, err := utils.Map(d.Get(utils.TerraformResourceLabels))
+	if err != nil {
+		return diag.Errorf("errored while flattening '%s' with :%v", utils.TerraformResourceLabels, err)
+	}
+
+	network := &k3dNetwork.Config{
+		Name:    utils.String(d.Get(utils.TerraformResourceName)),
+		Subnet:  utils.String(d.Get(utils.TerraformResourceNetworkSubnet)),
+		Gateway: utils.String(d.Get(utils.TerraformResourceGateway)),
+		IPRange: utils.String(d.Get(utils.TerraformResourceIPRange)),
+		Labels:  labels,
+	}
+
+	if err = network.Create(ctx, defaultConfig.K3DRuntime); err != nil {
+		return diag.Errorf("oops errored while creating network: %v", err)
+	}
+
+	d.SetId(network.ID)
+
+	return resourceNetworkRead(ctx, d, meta)
+}
+
+func resourceNetworkRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	defaultConfig := meta.(*client.Config)
+
+	cfg := &k3dNetwork.Config{ID: d.Id()}
+
+	network, err := cfg.Get(ctx, defaultConfig.K3DRuntime)
+	if err != nil {
+		if removeIfNotFound(d, err) {
+			return nil
+		}
+
+		return diag.Errorf("errored while fetching network '%s': %v", d.Id(), err)
+	}
+
+	// the network could be imported by its name, the ID of the network in runtime is what is tracked in state.
+	d.SetId(network.ID)
+
+	for key, value := range map[string]any{
+		utils.TerraformResourceName:          network.Name,
+		utils.TerraformResourceNetworkID:     network.ID,
+		utils.TerraformResourceNetworkSubnet: network.Subnet,
+		utils.TerraformResourceGateway:       network.Gateway,
+		utils.TerraformResourceIPRange:       network.IPRange,
+		utils.TerraformResourceLabels:        network.Labels,
+	} {
+		if err = d.Set(key, value); err != nil {
+			return diag.Errorf("oops setting '%s' errored with : %v", key, err)
+		}
+	}
+
+	return nil
+}
+
+func resourceNetworkDelete(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	defaultConfig := meta.(*client.Config)
+
+	network := &k3dNetwork.Config{
+		Name: utils.String(d.Get(utils.TerraformResourceName)),
+		ID:   d.Id(),
+	}
+
+	if err := network.Delete(ctx, defaultConfig.K3DRuntime); err != nil {
+		return diag.Errorf("oops errored while deleting network '%s': %v", network.Name, err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
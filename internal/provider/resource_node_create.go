@@ -2,21 +2,36 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"log"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/mitchellh/mapstructure"
 	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/client"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/k3d/cluster"
 	k3dNode "github.com/nikhilsbhat/terraform-provider-k3d/pkg/k3d/node"
 	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/utils"
+	"github.com/rancher/k3d/v5/pkg/runtimes"
+	K3D "github.com/rancher/k3d/v5/pkg/types"
+	"github.com/thoas/go-funk"
+	"k8s.io/client-go/kubernetes"
 )
 
 func resourceNode() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceNodeCreate,
 		ReadContext:   resourceNodeRead,
+		UpdateContext: resourceNodeUpdate,
 		DeleteContext: resourceNodeDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(utils.TerraformTimeOut10 * time.Minute),
+			Update: schema.DefaultTimeout(utils.TerraformTimeOut10 * time.Minute),
+			Delete: schema.DefaultTimeout(utils.TerraformTimeOut5 * time.Minute),
+		},
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -40,18 +55,20 @@ func resourceNode() *schema.Resource {
 				Description: "image to be used for nodes creation defaults to image declared in provider",
 			},
 			"role": {
-				Type:        schema.TypeString,
-				Computed:    false,
-				Optional:    true,
-				ForceNew:    true,
-				Description: "role to be assigned to the node(agent)",
+				Type:     schema.TypeString,
+				Computed: false,
+				Optional: true,
+				ForceNew: true,
+				Description: "role to be assigned to the node, either `agent` or `server` (defaults to `agent`), servers join the embedded etcd " +
+					"of the cluster and are put behind its loadbalancer, they can be added only to clusters with embedded etcd " +
+					"and added or removed only when etcd keeps its quorum with an odd number of servers in the cluster",
+				ValidateFunc: validation.StringInSlice([]string{string(K3D.AgentRole), string(K3D.ServerRole)}, false),
 			},
 			"replicas": {
 				Type:        schema.TypeInt,
 				Optional:    true,
 				Computed:    true,
-				ForceNew:    true,
-				Description: "number of nodes to be created",
+				Description: "number of nodes to be created, changing it adds or removes the nodes with the highest index without replacing the others",
 			},
 			"memory": {
 				Type:        schema.TypeString,
@@ -60,6 +77,56 @@ func resourceNode() *schema.Resource {
 				ForceNew:    true,
 				Description: "memory limit to be imposed on the node",
 			},
+			"k3s_args": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "additional arguments to be passed on to k3s running on the nodes, ex: `--kubelet-arg=max-pods=50`",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"labels": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "k3s node labels to be added to the nodes, same as `--node-label` of k3s",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"taints": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "k3s node taints to be added to the nodes in the format `key[=value]:effect`, same as `--node-taint` of k3s",
+				Elem: &schema.Schema{
+					Type:             schema.TypeString,
+					ValidateDiagFunc: validateNodeTaint,
+				},
+			},
+			"env": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "environment variables to be added to the nodes",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"volumes": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "volumes to be mounted into the nodes",
+				Elem: &schema.Resource{
+					Schema: resourceNodeVolumeSchema(),
+				},
+			},
+			"drain": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Description: "drains the kubernetes nodes through the kubernetes API of the cluster before the nodes are removed, " +
+					"either on destroy or when the replicas are reduced, the kubernetes node objects are removed along with the nodes",
+				Elem: &schema.Resource{
+					Schema: resourceNodeDrainSchema(),
+				},
+			},
 			"wait": {
 				Type:        schema.TypeBool,
 				Computed:    false,
@@ -71,7 +138,7 @@ func resourceNode() *schema.Resource {
 				Type:        schema.TypeInt,
 				Optional:    true,
 				Computed:    true,
-				Description: "maximum waiting time for before canceling/returning in minutes",
+				Description: "maximum waiting time for the nodes to be ready in minutes when 'wait' is enabled, bounded by the create timeout of the resource",
 			},
 			"creation_time": {
 				Type:        schema.TypeString,
@@ -114,16 +181,12 @@ func resourceNodeCreate(ctx context.Context, d *schema.ResourceData, meta any) d
 			return diag.Errorf("oops setting '%s' errored with : %v", utils.TerraformResourceCreatedAt, err)
 		}
 
-		nodeConfig := k3dNode.Config{
-			Name:              []string{utils.String(d.Get(utils.TerraformResourceName))},
-			ClusterAssociated: utils.String(d.Get(utils.TerraformResourceCluster)),
-			Image:             setNodeImage(d, defaultConfig),
-			Role:              utils.String(d.Get(utils.TerraformResourceRole)),
-			Count:             utils.Int(d.Get(utils.TerraformResourceReplicas)),
-			Memory:            utils.String(d.Get(utils.TerraformResourceMemory)),
-			Created:           createInitiatedAt.String(),
-			Timeout:           time.Duration(utils.Int(d.Get(utils.TerraformResourceTimeout))) * time.Minute,
-			Wait:              utils.Bool(d.Get(utils.TerraformResourceWait)),
+		nodeConfig := getNodeConfig(d, defaultConfig, createInitiatedAt.String())
+
+		if nodeConfig.Role == string(K3D.ServerRole) {
+			if err := validateNodeServers(ctx, defaultConfig.K3DRuntime, nil, nodeConfig.ClusterAssociated, nodeConfig.Count, nil); err != nil {
+				return diag.Errorf("adding servers to cluster '%s' errored with: %v", nodeConfig.ClusterAssociated, err)
+			}
 		}
 
 		if err := nodeConfig.CreateNodes(ctx, defaultConfig.K3DRuntime, 0); err != nil {
@@ -151,7 +214,14 @@ func resourceNodeRead(ctx context.Context, d *schema.ResourceData, meta any) dia
 
 	k3dNodes, err := cfg.GetNodesByLabels(ctx, defaultConfig.K3DRuntime)
 	if err != nil {
-		return diag.Errorf("errored while fetching created nodes: %v", k3dNodes)
+		return diag.Errorf("errored while fetching created nodes: %v", err)
+	}
+
+	if len(k3dNodes) == 0 {
+		log.Printf("nodes '%s' not found, removing them from state", d.Id())
+		d.SetId("")
+
+		return nil
 	}
 
 	flattenedK3dNodes, err := utils.MapSlice(k3dNodes)
@@ -163,9 +233,78 @@ func resourceNodeRead(ctx context.Context, d *schema.ResourceData, meta any) dia
 		return diag.Errorf("oops setting '%s' errored with : %v", utils.TerraformResourceNodes, err)
 	}
 
+	if err = d.Set(utils.TerraformResourceReplicas, len(k3dNodes)); err != nil {
+		return diag.Errorf("oops setting '%s' errored with : %v", utils.TerraformResourceReplicas, err)
+	}
+
+	// nodes created without settings of their own copy the ones of an existing node of the cluster, which are not of the resource.
+	if !getNodeConfig(d, defaultConfig, created).HasOwnSettings() {
+		return nil
+	}
+
+	return setNodeOwnSettings(ctx, d, defaultConfig.K3DRuntime, k3dNodes[0])
+}
+
+// setNodeOwnSettings sets the node labels, node taints, k3s arguments, environment variables and volumes the nodes were created with,
+// read from the node specified since all the nodes of the resource are created with the same settings.
+func setNodeOwnSettings(ctx context.Context, d *schema.ResourceData, runtime runtimes.Runtime, node *k3dNode.Config) diag.Diagnostics {
+	inspector, err := k3dNode.NewInspector(runtime)
+	if err != nil {
+		return diag.Errorf("inspecting node '%s' errored with: %v", node.Name[0], err)
+	}
+
+	defer inspector.Close()
+
+	image, err := inspector.GetNodeImage(ctx, node.Name[0])
+	if err != nil {
+		return diag.Errorf("fetching image of node '%s' errored with: %v", node.Name[0], err)
+	}
+
+	settings := node.GetOwnSettings(image.Env)
+
+	for key, value := range map[string]any{
+		utils.TerraformResourceLabels:  settings.K3sNodeLabels,
+		utils.TerraformResourceTaints:  settings.K3sNodeTaints,
+		utils.TerraformResourceK3sArgs: settings.K3sArgs,
+		utils.TerraformResourceEnv:     getNodeEnv(settings.EnvironmentVariables),
+		utils.TerraformResourceVolumes: getNodeVolumes(settings.Volumes),
+	} {
+		if err = d.Set(key, value); err != nil {
+			return diag.Errorf("oops setting '%s' errored with : %v", key, err)
+		}
+	}
+
 	return nil
 }
 
+// getNodeEnv returns the environment variables of the node, in the format KEY=VALUE, as a map of the key to the value.
+func getNodeEnv(envs []string) map[string]string {
+	env := make(map[string]string, len(envs))
+
+	for _, keyValue := range envs {
+		key, value, _ := strings.Cut(keyValue, "=")
+		env[key] = value
+	}
+
+	return env
+}
+
+// getNodeVolumes returns the volumes mounted into the node, [SOURCE:]DEST[:MODE], in the format of volumes of the resource.
+func getNodeVolumes(volumes []string) []any {
+	nodeVolumes := make([]any, 0, len(volumes))
+
+	for _, volume := range volumes {
+		source, destination, mode := getVolumeMount(volume)
+		nodeVolumes = append(nodeVolumes, map[string]any{
+			"source":      source,
+			"destination": destination,
+			"mode":        mode,
+		})
+	}
+
+	return nodeVolumes
+}
+
 func resourceNodeDelete(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
 	defaultConfig := meta.(*client.Config)
 
@@ -182,7 +321,40 @@ func resourceNodeDelete(ctx context.Context, d *schema.ResourceData, meta any) d
 		return diag.Errorf("oops reading nodes from state errored with : %s", err.Error())
 	}
 
+	servers := funk.Filter(nodes, func(node *k3dNode.Config) bool {
+		return node.Role == string(K3D.ServerRole)
+	}).([]*k3dNode.Config)
+
+	var kubeClient kubernetes.Interface
+
+	if len(servers) != 0 {
+		serverNames := make([]string, 0, len(servers))
+		for _, server := range servers {
+			serverNames = append(serverNames, server.Name...)
+		}
+
+		clusterName := utils.String(d.Get(utils.TerraformResourceCluster))
+
+		// the servers are removed from embedded etcd through the kubernetes API before their containers are deleted.
+		var err error
+		if kubeClient, err = getClusterKubeClient(ctx, defaultConfig.K3DRuntime, clusterName); err != nil {
+			return diag.Errorf("removing servers %v from cluster '%s' errored with: %v", serverNames, clusterName, err)
+		}
+
+		if err = validateNodeServers(ctx, defaultConfig.K3DRuntime, kubeClient, clusterName, 0, serverNames); err != nil {
+			return diag.Errorf("removing servers %v from cluster '%s' errored with: %v", serverNames, clusterName, err)
+		}
+	}
+
+	drainCfg, err := getNodeDrainConfig(ctx, d, defaultConfig.K3DRuntime)
+	if err != nil {
+		return diag.Errorf("fetching %s of nodes errored with: %v", utils.TerraformResourceDrain, err)
+	}
+
 	for _, node := range nodes {
+		node.Drain = drainCfg
+		node.KubeClient = kubeClient
+
 		if err := node.DeleteNodesFromCluster(ctx, defaultConfig.K3DRuntime); err != nil {
 			return diag.Errorf("oops deleting node %s errored with : %s", node.Name[0], err.Error())
 		}
@@ -193,6 +365,67 @@ func resourceNodeDelete(ctx context.Context, d *schema.ResourceData, meta any) d
 	return nil
 }
 
+// getNodeConfig returns the config of the nodes of the resource, the nodes created are labelled with the creation time so that they could be found later.
+func getNodeConfig(d *schema.ResourceData, defaultConfig *client.Config, created string) *k3dNode.Config {
+	return &k3dNode.Config{
+		Name:                 []string{utils.String(d.Get(utils.TerraformResourceName))},
+		ClusterAssociated:    utils.String(d.Get(utils.TerraformResourceCluster)),
+		Image:                setNodeImage(d, defaultConfig),
+		Role:                 getNodeRole(d),
+		Count:                utils.Int(d.Get(utils.TerraformResourceReplicas)),
+		Memory:               utils.String(d.Get(utils.TerraformResourceMemory)),
+		Created:              created,
+		Timeout:              time.Duration(utils.Int(d.Get(utils.TerraformResourceTimeout))) * time.Minute,
+		Wait:                 utils.Bool(d.Get(utils.TerraformResourceWait)),
+		K3sArgs:              utils.GetSlice(d.Get(utils.TerraformResourceK3sArgs).([]any)),
+		K3sNodeLabels:        flattenStringMap(d.Get(utils.TerraformResourceLabels)),
+		K3sNodeTaints:        utils.GetSlice(d.Get(utils.TerraformResourceTaints).([]any)),
+		EnvironmentVariables: flattenNodeEnv(d.Get(utils.TerraformResourceEnv)),
+		Volumes:              flattenNodeVolumes(d.Get(utils.TerraformResourceVolumes).([]any)),
+	}
+}
+
+// getNodeRole returns the role of the nodes, which defaults to agent.
+func getNodeRole(d *schema.ResourceData) string {
+	role := utils.String(d.Get(utils.TerraformResourceRole))
+	if len(role) == 0 {
+		return string(K3D.AgentRole)
+	}
+
+	return role
+}
+
+// validateNodeServers validates that adding and removing the specified servers would keep the quorum of embedded etcd,
+// without leaving the cluster with an even number of servers. Only the servers that exist in the cluster are counted as removed,
+// the members of etcd are counted through kubeClient when it is set, which is required for removing the servers.
+func validateNodeServers(ctx context.Context, runtime runtimes.Runtime, kubeClient kubernetes.Interface, clusterName string, added int, removed []string) error {
+	servers, err := runtime.GetNodesByLabel(ctx, map[string]string{
+		K3D.LabelClusterName: clusterName,
+		K3D.LabelRole:        string(K3D.ServerRole),
+	})
+	if err != nil {
+		return err
+	}
+
+	removedServers := funk.Filter(servers, func(server *K3D.Node) bool {
+		return funk.ContainsString(removed, server.Name)
+	}).([]*K3D.Node)
+
+	embeddedEtcd, err := cluster.HasEmbeddedEtcd(ctx, runtime, clusterName)
+	if err != nil {
+		return err
+	}
+
+	members := len(servers)
+	if kubeClient != nil {
+		if members, err = cluster.GetEtcdMembers(ctx, kubeClient); err != nil {
+			return fmt.Errorf("fetching etcd members of cluster '%s' errored with: %w", clusterName, err)
+		}
+	}
+
+	return cluster.ValidateServerQuorum(members, len(servers), len(servers)+added-len(removedServers), embeddedEtcd)
+}
+
 func setNodeImage(d *schema.ResourceData, defaultConfig *client.Config) string {
 	image := utils.String(d.Get(utils.TerraformResourceImage))
 	if len(image) == 0 {
@@ -0,0 +1,172 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/client"
+	terraformErrors "github.com/nikhilsbhat/terraform-provider-k3d/pkg/errors"
+	K3D "github.com/rancher/k3d/v5/pkg/types"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetNodeConfig(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceNode().Schema, map[string]any{
+		"name":     "gpu",
+		"cluster":  "test",
+		"image":    "rancher/k3s:v1.24.4-k3s1",
+		"role":     "agent",
+		"replicas": 3,
+		"k3s_args": []any{"--kubelet-arg=max-pods=50"},
+		"labels":   map[string]any{"tier": "gpu"},
+		"taints":   []any{"dedicated=gpu:NoSchedule"},
+		"env":      map[string]any{"NVIDIA_VISIBLE_DEVICES": "all", "LOG": "debug"},
+		"volumes": []any{
+			map[string]any{"source": "/opt/models", "destination": "/models", "mode": "ro"},
+		},
+	})
+
+	nodeConfig := getNodeConfig(d, &client.Config{}, "2022-10-10 10:10:10")
+
+	if got, want := nodeConfig.Count, 3; got != want {
+		t.Fatalf("expected %d replicas, got %d", want, got)
+	}
+
+	if got, want := nodeConfig.Created, "2022-10-10 10:10:10"; got != want {
+		t.Fatalf("expected nodes to be labelled with creation time %q, got %q", want, got)
+	}
+
+	if got, want := nodeConfig.K3sNodeLabels, map[string]string{"tier": "gpu"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected node labels %v, got %v", want, got)
+	}
+
+	if got, want := nodeConfig.GetK3sNodeArgs(), []string{"--node-taint=dedicated=gpu:NoSchedule", "--kubelet-arg=max-pods=50"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected k3s args %v, got %v", want, got)
+	}
+
+	if got, want := nodeConfig.EnvironmentVariables, []string{"LOG=debug", "NVIDIA_VISIBLE_DEVICES=all"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected env %v, got %v", want, got)
+	}
+
+	if got, want := nodeConfig.Volumes, []string{"/opt/models:/models:ro"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected volumes %v, got %v", want, got)
+	}
+}
+
+func TestGetNodeVolumes(t *testing.T) {
+	volumes := []any{
+		map[string]any{"source": "/opt/models", "destination": "/models", "mode": "ro"},
+		map[string]any{"source": "k3d-cache", "destination": "/var/cache", "mode": ""},
+	}
+
+	// the volumes read back from the nodes should match the ones the nodes were created with.
+	if got := getNodeVolumes(flattenNodeVolumes(volumes)); !reflect.DeepEqual(got, volumes) {
+		t.Fatalf("expected volumes %v, got %v", volumes, got)
+	}
+
+	if got, want := getNodeEnv([]string{"LOG=debug", "OPTS=a=b"}), map[string]string{"LOG": "debug", "OPTS": "a=b"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected env %v, got %v", want, got)
+	}
+}
+
+func TestResourceNodeReadReplicas(t *testing.T) {
+	// one of the three nodes was removed outside of terraform.
+	runtime := &stubRuntime{nodes: []*K3D.Node{
+		{Name: "k3d-gpu-0", Role: K3D.AgentRole},
+		{Name: "k3d-gpu-1", Role: K3D.AgentRole},
+	}}
+
+	d := schema.TestResourceDataRaw(t, resourceNode().Schema, map[string]any{
+		"name":     "gpu",
+		"cluster":  "test",
+		"replicas": 3,
+	})
+	d.SetId("gpu")
+
+	if diags := resourceNodeRead(context.Background(), d, &client.Config{K3DRuntime: runtime}); diags.HasError() {
+		t.Fatalf("expected nodes to be read, got %v", diags)
+	}
+
+	if got, want := d.Get("replicas").(int), 2; got != want {
+		t.Fatalf("expected %d replicas to be read back, got %d", want, got)
+	}
+}
+
+func TestFlattenNodeDrain(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceNode().Schema, map[string]any{
+		"name":    "agent",
+		"cluster": "test",
+	})
+
+	drainCfg, err := flattenNodeDrain(d.Get("drain"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if drainCfg != nil {
+		t.Fatalf("expected nodes not to be drained without drain block, got %+v", drainCfg)
+	}
+
+	d = schema.TestResourceDataRaw(t, resourceNode().Schema, map[string]any{
+		"name":    "agent",
+		"cluster": "test",
+		"drain": []any{
+			map[string]any{"timeout": "2m", "delete_emptydir_data": true},
+		},
+	})
+
+	drainCfg, err = flattenNodeDrain(d.Get("drain"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if got, want := drainCfg.Timeout, 2*time.Minute; got != want {
+		t.Fatalf("expected drain timeout %s, got %s", want, got)
+	}
+
+	if !drainCfg.IgnoreDaemonSets || !drainCfg.DeleteEmptyDirData {
+		t.Fatalf("expected daemonsets to be ignored and emptyDir data to be deleted, got %+v", drainCfg)
+	}
+
+	if got, want := drainCfg.GracePeriod, -1; got != want {
+		t.Fatalf("expected grace period %d, got %d", want, got)
+	}
+}
+
+func TestValidateNodeServersOnCreateAndDelete(t *testing.T) {
+	ctx := context.Background()
+	initServer := &K3D.Node{Name: "k3d-test-server-0", Role: K3D.ServerRole, RuntimeLabels: map[string]string{K3D.LabelServerIsInit: "true"}}
+	runtime := &stubRuntime{nodes: []*K3D.Node{initServer}}
+
+	if err := validateNodeServers(ctx, runtime, nil, "test", 1, nil); !errors.Is(err, terraformErrors.ErrEvenServers) {
+		t.Fatalf("expected adding a single server to a cluster with one server to fail with %v, got %v", terraformErrors.ErrEvenServers, err)
+	}
+
+	if err := validateNodeServers(ctx, runtime, nil, "test", 2, nil); err != nil {
+		t.Fatalf("expected adding two servers to a cluster with one server to succeed, got %v", err)
+	}
+
+	runtime.nodes = append(runtime.nodes,
+		&K3D.Node{Name: "k3d-control-plane-0", Role: K3D.ServerRole},
+		&K3D.Node{Name: "k3d-control-plane-1", Role: K3D.ServerRole},
+	)
+
+	kubeClient := fake.NewSimpleClientset()
+
+	for _, server := range runtime.nodes {
+		etcdNode := &coreV1.Node{ObjectMeta: metaV1.ObjectMeta{Name: server.Name, Labels: map[string]string{"node-role.kubernetes.io/etcd": "true"}}}
+		if _, err := kubeClient.CoreV1().Nodes().Create(ctx, etcdNode, metaV1.CreateOptions{}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	if err := validateNodeServers(ctx, runtime, kubeClient, "test", 0, []string{"k3d-control-plane-0", "k3d-control-plane-1"}); err != nil {
+		t.Fatalf("expected the servers added to be removed on destroy, got %v", err)
+	}
+}
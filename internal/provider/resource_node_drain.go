@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/k3d/cluster"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/utils"
+	"github.com/rancher/k3d/v5/pkg/runtimes"
+)
+
+const nodeDrainInterval = 2 * time.Second
+
+// getNodeDrainConfig returns how the nodes are drained along with the client of the kubernetes API of their cluster,
+// it returns nil when the nodes are not to be drained.
+func getNodeDrainConfig(ctx context.Context, d *schema.ResourceData, runtime runtimes.Runtime) (*cluster.DrainConfig, error) {
+	drainCfg, err := flattenNodeDrain(d.Get(utils.TerraformResourceDrain))
+	if err != nil || drainCfg == nil {
+		return drainCfg, err
+	}
+
+	if drainCfg.KubeClient, err = getClusterKubeClient(ctx, runtime, utils.String(d.Get(utils.TerraformResourceCluster))); err != nil {
+		return nil, err
+	}
+
+	return drainCfg, nil
+}
+
+func flattenNodeDrain(drain any) (*cluster.DrainConfig, error) {
+	drainList := drain.([]any)
+	if len(drainList) == 0 || drainList[0] == nil {
+		return nil, nil //nolint:nilnil
+	}
+
+	dr := drainList[0].(map[string]any)
+
+	timeout, err := time.ParseDuration(utils.String(dr["timeout"]))
+	if err != nil {
+		return nil, err
+	}
+
+	return &cluster.DrainConfig{
+		Timeout:            timeout,
+		Interval:           nodeDrainInterval,
+		IgnoreDaemonSets:   utils.Bool(dr["ignore_daemonsets"]),
+		DeleteEmptyDirData: utils.Bool(dr["delete_emptydir_data"]),
+		GracePeriod:        utils.Int(dr["grace_period"]),
+	}, nil
+}
@@ -1,6 +1,9 @@
 package provider
 
-import "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
 
 func resourceNodeSchema() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
@@ -68,5 +71,57 @@ func resourceNodeSchema() map[string]*schema.Schema {
 			Description: "environment variables set in the node",
 			Elem:        &schema.Schema{Type: schema.TypeString},
 		},
+		"k3s_args": {
+			Type:        schema.TypeList,
+			Computed:    true,
+			Optional:    true,
+			Description: "arguments passed on to k3s running on the node, other than the node labels and taints",
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+		"k3s_node_labels": {
+			Type:        schema.TypeMap,
+			Computed:    true,
+			Optional:    true,
+			Description: "k3s node labels the node is registered with",
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+		"k3s_node_taints": {
+			Type:        schema.TypeList,
+			Computed:    true,
+			Optional:    true,
+			Description: "k3s node taints the node is registered with",
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+	}
+}
+
+func resourceNodeDrainSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"timeout": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "5m",
+			Description:  "maximum duration to wait for the pods of a node to be evicted",
+			ValidateFunc: validateDuration,
+		},
+		"ignore_daemonsets": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     true,
+			Description: "leaves the pods of daemonsets on the node, otherwise the drain fails when the node runs any of them",
+		},
+		"delete_emptydir_data": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "evicts the pods using emptyDir volumes whose data would be lost, otherwise the drain fails when the node runs any of them",
+		},
+		"grace_period": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Default:      -1,
+			Description:  "time in seconds given to the pods to terminate, negative value uses the grace period of the pod",
+			ValidateFunc: validation.IntAtLeast(-1),
+		},
 	}
 }
@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/client"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/utils"
+	K3D "github.com/rancher/k3d/v5/pkg/types"
+)
+
+// resourceNodeUpdate scales the nodes in place when the replicas are changed, the nodes are added after the highest existing index
+// and the ones with the highest index are removed first. The nodes added carry the same creation time label as the existing ones,
+// so that all of them are read back.
+func resourceNodeUpdate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	defaultConfig := meta.(*client.Config)
+
+	if !d.HasChange(utils.TerraformResourceReplicas) {
+		return resourceNodeRead(ctx, d, meta)
+	}
+
+	nodeConfig := getNodeConfig(d, defaultConfig, utils.String(d.Get(utils.TerraformResourceCreatedAt)))
+
+	oldReplicas, _ := d.GetChange(utils.TerraformResourceReplicas)
+
+	if nodeConfig.Role == string(K3D.ServerRole) {
+		existingNodes, err := nodeConfig.GetIndexedNodes(ctx, defaultConfig.K3DRuntime)
+		if err != nil {
+			return diag.Errorf("fetching nodes '%s' of cluster '%s' errored with: %v", nodeConfig.Name[0], nodeConfig.ClusterAssociated, err)
+		}
+
+		added, removed := nodeConfig.Count-len(existingNodes), make([]string, 0)
+		if added < 0 {
+			added, removed = 0, existingNodes[nodeConfig.Count:]
+
+			// the servers removed are removed from embedded etcd through the kubernetes API before their containers are deleted.
+			if nodeConfig.KubeClient, err = getClusterKubeClient(ctx, defaultConfig.K3DRuntime, nodeConfig.ClusterAssociated); err != nil {
+				d.Partial(true)
+
+				return diag.Errorf("scaling servers '%s' of cluster '%s' to %d errored with: %v",
+					nodeConfig.Name[0], nodeConfig.ClusterAssociated, nodeConfig.Count, err)
+			}
+		}
+
+		if err = validateNodeServers(ctx, defaultConfig.K3DRuntime, nodeConfig.KubeClient, nodeConfig.ClusterAssociated, added, removed); err != nil {
+			d.Partial(true)
+
+			return diag.Errorf("scaling servers '%s' of cluster '%s' to %d errored with: %v",
+				nodeConfig.Name[0], nodeConfig.ClusterAssociated, nodeConfig.Count, err)
+		}
+	}
+
+	// the nodes removed are drained, the drain is not needed when the nodes are only added.
+	if nodeConfig.Count < utils.Int(oldReplicas) {
+		drainCfg, err := getNodeDrainConfig(ctx, d, defaultConfig.K3DRuntime)
+		if err != nil {
+			d.Partial(true)
+
+			return diag.Errorf("fetching %s of nodes errored with: %v", utils.TerraformResourceDrain, err)
+		}
+
+		nodeConfig.Drain = drainCfg
+	}
+
+	log.Printf("scaling nodes '%s' of cluster '%s' from %d to %d",
+		nodeConfig.Name[0], nodeConfig.ClusterAssociated, utils.Int(oldReplicas), nodeConfig.Count)
+
+	if err := nodeConfig.ScaleNodes(ctx, defaultConfig.K3DRuntime, nodeConfig.Count); err != nil {
+		// the earlier replicas are left in the state, so that the scaling is retried on next apply.
+		d.Partial(true)
+
+		return diag.Errorf("scaling nodes '%s' of cluster '%s' errored with: %v", nodeConfig.Name[0], nodeConfig.ClusterAssociated, err)
+	}
+
+	return resourceNodeRead(ctx, d, meta)
+}
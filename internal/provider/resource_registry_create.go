@@ -2,6 +2,7 @@ package provider
 
 import (
 	"context"
+	"log"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -166,7 +167,14 @@ func resourceRegistryRead(ctx context.Context, d *schema.ResourceData, meta any)
 
 	registries, err := registry.Get(ctx, defaultConfig.K3DRuntime)
 	if err != nil {
-		return diag.Errorf("errored while fetching registries: '%s'", registryName)
+		return diag.Errorf("errored while fetching registries: '%s': %v", registryName, err)
+	}
+
+	if len(registries) == 0 {
+		log.Printf("registry '%s' not found, removing it from state", registryName)
+		d.SetId("")
+
+		return nil
 	}
 
 	flattenedRegistryNodes, err := utils2.MapSlice(registries)
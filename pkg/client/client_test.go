@@ -2,9 +2,11 @@
 package client
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 
+	terraformErrors "github.com/nikhilsbhat/terraform-provider-k3d/pkg/errors"
 	"github.com/rancher/k3d/v5/pkg/runtimes"
 )
 
@@ -33,3 +35,19 @@ func Test_getRuntime(t *testing.T) {
 		})
 	}
 }
+
+type stubRuntime struct {
+	runtimes.Runtime
+}
+
+func (runtime *stubRuntime) ID() string {
+	return "containerd"
+}
+
+func TestGetDockerClient(t *testing.T) {
+	t.Run("should error for the runtimes other than docker", func(t *testing.T) {
+		if _, err := GetDockerClient(&stubRuntime{}); !errors.Is(err, terraformErrors.ErrUnsupportedRuntime) {
+			t.Errorf("GetDockerClient() error = %v, want %v", err, terraformErrors.ErrUnsupportedRuntime)
+		}
+	})
+}
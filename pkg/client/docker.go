@@ -0,0 +1,20 @@
+package client
+
+import (
+	"fmt"
+
+	dockerClient "github.com/docker/docker/client"
+	terraformErrors "github.com/nikhilsbhat/terraform-provider-k3d/pkg/errors"
+	"github.com/rancher/k3d/v5/pkg/runtimes"
+	"github.com/rancher/k3d/v5/pkg/runtimes/docker"
+)
+
+// GetDockerClient returns the client of docker behind the runtime, for the details of the containers and networks
+// that k3d does not report through the runtime. The caller is expected to close the client once done with it.
+func GetDockerClient(runtime runtimes.Runtime) (dockerClient.APIClient, error) {
+	if runtime.ID() != runtimes.Docker.ID() {
+		return nil, fmt.Errorf("%w: '%s'", terraformErrors.ErrUnsupportedRuntime, runtime.ID())
+	}
+
+	return docker.GetDockerClient()
+}
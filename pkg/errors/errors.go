@@ -1,16 +1,107 @@
 package errors
 
-import stdErrors "errors"
+import (
+	stdErrors "errors"
+	"fmt"
+
+	runtimeErrors "github.com/rancher/k3d/v5/pkg/runtimes/errors"
+)
 
 var (
 	ErrClusterAlreadyExists    = stdErrors.New("cluster already exists")
+	ErrClusterNotReady         = stdErrors.New("cluster is not ready")
 	ErrConfigFileReference     = stdErrors.New("for more info refer 'https://k3d.io/usage/configfile/'")
+	ErrConflictingK3sArg       = stdErrors.New("k3s argument is set by both k3s and k3s_options.extra_args")
 	ErrCreateNodesFailed       = stdErrors.New("creating nodes failed")
 	ErrDeleteNodesFailed       = stdErrors.New("deleting nodes failed")
+	ErrDuplicateHostPort       = stdErrors.New("host port is mapped more than once")
+	ErrEtcdQuorumLost          = stdErrors.New("scaling servers would leave embedded etcd without quorum")
+	ErrEvenServers             = stdErrors.New("scaling servers would leave the cluster with an even number of servers")
 	ErrGenerateRandomBytes     = stdErrors.New("error generating random bytes")
 	ErrImportImagesFailed      = stdErrors.New("importing images to clusters errored")
 	ErrInsufficientRandomBytes = stdErrors.New("generated insufficient random bytes")
+	ErrInvalidAddon            = stdErrors.New("addon should have either manifest or helm_chart")
+	ErrInvalidContextName      = stdErrors.New("context name template is invalid")
+	ErrInvalidKubeConfig       = stdErrors.New("kubeconfig does not have the entry referred by current context")
 	ErrInvalidMemoryLimit      = stdErrors.New("provided memory limit value is invalid")
+	ErrInvalidNodePool         = stdErrors.New("node pool is invalid")
+	ErrInvalidPortRange        = stdErrors.New("port range is invalid")
+	ErrInvalidRegistriesConfig = stdErrors.New("registries config is invalid")
+	ErrInvalidSimpleConfig     = stdErrors.New("k3d config is invalid")
+	ErrInvalidTaint            = stdErrors.New("taint is invalid")
+	ErrInvalidVolume           = stdErrors.New("volume is invalid")
+	ErrMinimumServers          = stdErrors.New("cluster should have at least one server")
+	ErrNetworkInUse            = stdErrors.New("network is still used by k3d nodes")
+	ErrNodeDrainFailed         = stdErrors.New("draining kubernetes node failed")
 	ErrNodeNotFound            = stdErrors.New("nodes not found to start/stop them")
+	ErrNoEmbeddedEtcd          = stdErrors.New("cluster was not initialised with embedded etcd, servers cannot be added")
 	ErrUnsupportedKind         = stdErrors.New("unsupported kind, only supported value is Simple")
+	ErrUnsupportedRuntime      = stdErrors.New("unsupported runtime, only supported value is docker")
+	ErrUnsupportedWorkload     = stdErrors.New("unsupported workload, supported values are deployment and daemonset")
 )
+
+// NotFoundError is returned when the k3d object looked up no longer exists in the runtime.
+type NotFoundError struct {
+	Kind string
+	Name string
+	Err  error
+}
+
+func (e *NotFoundError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s '%s' not found: %v", e.Kind, e.Name, e.Err)
+	}
+
+	return fmt.Sprintf("%s '%s' not found", e.Kind, e.Name)
+}
+
+func (e *NotFoundError) Unwrap() error {
+	return e.Err
+}
+
+// NewNotFoundError returns NotFoundError for the object of the specified kind and name.
+func NewNotFoundError(kind, name string) error {
+	return &NotFoundError{Kind: kind, Name: name}
+}
+
+// InvalidConfigError is returned when k3d fails to transform or validate the cluster config,
+// Field is the field of the SimpleConfig that made it invalid.
+type InvalidConfigError struct {
+	Field string
+	Err   error
+}
+
+func (e *InvalidConfigError) Error() string {
+	return fmt.Sprintf("%v: field '%s': %v", ErrInvalidSimpleConfig, e.Field, e.Err)
+}
+
+func (e *InvalidConfigError) Unwrap() []error {
+	return []error{ErrInvalidSimpleConfig, e.Err}
+}
+
+// IsNotFound checks if the error or any of the errors it wraps is a NotFoundError.
+func IsNotFound(err error) bool {
+	var notFoundError *NotFoundError
+
+	return stdErrors.As(err, &notFoundError)
+}
+
+// ClassifyLookupError translates the errors returned by k3d or the runtime while looking up an object, which indicates
+// that the object does not exist, to NotFoundError. Any other error is a runtime failure and is returned as is.
+func ClassifyLookupError(kind, name string, err error) error {
+	if err == nil || IsNotFound(err) {
+		return err
+	}
+
+	for _, notFoundErr := range []error{
+		runtimeErrors.ErrRuntimeNetworkNotExists,
+		runtimeErrors.ErrRuntimeVolumeNotExists,
+		runtimeErrors.ErrRuntimeFileNotFound,
+	} {
+		if stdErrors.Is(err, notFoundErr) {
+			return &NotFoundError{Kind: kind, Name: name, Err: err}
+		}
+	}
+
+	return err
+}
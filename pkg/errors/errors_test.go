@@ -0,0 +1,52 @@
+package errors_test
+
+import (
+	stdErrors "errors"
+	"fmt"
+	"testing"
+
+	terraformErrors "github.com/nikhilsbhat/terraform-provider-k3d/pkg/errors"
+	k3dClient "github.com/rancher/k3d/v5/pkg/client"
+	runtimeErrors "github.com/rancher/k3d/v5/pkg/runtimes/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyLookupError(t *testing.T) {
+	t.Run("should not classify cluster without nodes as not found since k3d returns it for runtime failures", func(t *testing.T) {
+		lookupErr := fmt.Errorf("lookup failed: %w", k3dClient.ClusterGetNoNodesFoundError)
+		err := terraformErrors.ClassifyLookupError("cluster", "test", lookupErr)
+		assert.False(t, terraformErrors.IsNotFound(err))
+		assert.Equal(t, lookupErr, err)
+	})
+
+	t.Run("should classify missing volume as not found", func(t *testing.T) {
+		err := terraformErrors.ClassifyLookupError("volume", "test", fmt.Errorf("lookup failed: %w", runtimeErrors.ErrRuntimeVolumeNotExists))
+		assert.True(t, terraformErrors.IsNotFound(err))
+		assert.ErrorIs(t, err, runtimeErrors.ErrRuntimeVolumeNotExists)
+		assert.EqualError(t, err, "volume 'test' not found: lookup failed: volume does not exist")
+	})
+
+	t.Run("should classify missing network as not found", func(t *testing.T) {
+		err := terraformErrors.ClassifyLookupError("network", "test", runtimeErrors.ErrRuntimeNetworkNotExists)
+		assert.True(t, terraformErrors.IsNotFound(err))
+	})
+
+	t.Run("should return runtime failures as is", func(t *testing.T) {
+		runtimeErr := stdErrors.New("cannot connect to the docker daemon")
+		err := terraformErrors.ClassifyLookupError("cluster", "test", runtimeErr)
+		assert.False(t, terraformErrors.IsNotFound(err))
+		assert.Equal(t, runtimeErr, err)
+	})
+
+	t.Run("should return nil when there is no error", func(t *testing.T) {
+		assert.NoError(t, terraformErrors.ClassifyLookupError("cluster", "test", nil))
+	})
+}
+
+func TestIsNotFound(t *testing.T) {
+	t.Run("should identify wrapped not found errors", func(t *testing.T) {
+		err := fmt.Errorf("reading node errored: %w", terraformErrors.NewNotFoundError("node", "test-node"))
+		assert.True(t, terraformErrors.IsNotFound(err))
+		assert.EqualError(t, err, "reading node errored: node 'test-node' not found")
+	})
+}
@@ -0,0 +1,192 @@
+package cluster
+
+import (
+	"archive/tar"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path"
+
+	terraformErrors "github.com/nikhilsbhat/terraform-provider-k3d/pkg/errors"
+	"github.com/rancher/k3d/v5/pkg/runtimes"
+	K3D "github.com/rancher/k3d/v5/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// AddonsManifestsDir is the directory from which k3s auto applies the manifests.
+	AddonsManifestsDir = "/var/lib/rancher/k3s/server/manifests"
+	addonFilePrefix    = "addon-"
+	addonFileMode      = 0o644
+	helmChartNamespace = "kube-system"
+)
+
+// Addon is either a raw manifest or a HelmChart that k3s applies to the cluster.
+type Addon struct {
+	Name      string
+	Manifest  string
+	HelmChart *HelmChart
+}
+
+// HelmChart holds the spec of the HelmChart resource deployed by the helm controller of k3s.
+type HelmChart struct {
+	Chart           string
+	Repo            string
+	Version         string
+	TargetNamespace string
+	Values          string
+}
+
+// Render renders the manifest of the addon, for HelmChart a helm.cattle.io/v1 HelmChart resource is rendered.
+func (addon *Addon) Render() ([]byte, error) {
+	if addon.HelmChart == nil {
+		if len(addon.Manifest) == 0 {
+			return nil, fmt.Errorf("%w: '%s' has neither manifest nor helm_chart", terraformErrors.ErrInvalidAddon, addon.Name)
+		}
+
+		return []byte(addon.Manifest), nil
+	}
+
+	if len(addon.Manifest) != 0 {
+		return nil, fmt.Errorf("%w: '%s' has both manifest and helm_chart", terraformErrors.ErrInvalidAddon, addon.Name)
+	}
+
+	spec := map[string]any{"chart": addon.HelmChart.Chart}
+
+	for key, value := range map[string]string{
+		"repo":            addon.HelmChart.Repo,
+		"version":         addon.HelmChart.Version,
+		"targetNamespace": addon.HelmChart.TargetNamespace,
+		"valuesContent":   addon.HelmChart.Values,
+	} {
+		if len(value) != 0 {
+			spec[key] = value
+		}
+	}
+
+	return yaml.Marshal(map[string]any{
+		"apiVersion": "helm.cattle.io/v1",
+		"kind":       "HelmChart",
+		"metadata": map[string]any{
+			"name":      addon.Name,
+			"namespace": helmChartNamespace,
+		},
+		"spec": spec,
+	})
+}
+
+// Hash returns the sha256 of the rendered manifest of the addon.
+func (addon *Addon) Hash() (string, error) {
+	content, err := addon.Render()
+	if err != nil {
+		return "", err
+	}
+
+	return HashAddonContent(content), nil
+}
+
+// HashAddonContent returns the sha256 of the manifest.
+func HashAddonContent(content []byte) string {
+	hash := sha256.Sum256(content)
+
+	return hex.EncodeToString(hash[:])
+}
+
+// AddonFilePath returns the path of the file in the server nodes to which the manifest of the addon is written.
+func AddonFilePath(name string) string {
+	return path.Join(AddonsManifestsDir, fmt.Sprintf("%s%s.yaml", addonFilePrefix, name))
+}
+
+// WriteAddons writes the manifests of the addons into all the server nodes, overwriting the existing ones.
+func WriteAddons(ctx context.Context, runtime runtimes.Runtime, servers []*K3D.Node, addons []*Addon) error {
+	for _, addon := range addons {
+		content, err := addon.Render()
+		if err != nil {
+			return err
+		}
+
+		for _, server := range servers {
+			if err = runtime.WriteToNode(ctx, content, AddonFilePath(addon.Name), addonFileMode, server); err != nil {
+				return fmt.Errorf("writing addon '%s' to node '%s' errored with: %w", addon.Name, server.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// RemoveAddons removes the manifests of the addons from all the server nodes.
+func RemoveAddons(ctx context.Context, runtime runtimes.Runtime, servers []*K3D.Node, names []string) error {
+	for _, name := range names {
+		for _, server := range servers {
+			if err := runtime.ExecInNode(ctx, server, []string{"rm", "-f", AddonFilePath(name)}); err != nil {
+				return fmt.Errorf("removing addon '%s' from node '%s' errored with: %w", name, server.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// GetAddonHashes reads the manifests of the addons from all the server nodes and returns their sha256,
+// addons whose manifest are missing in any of the nodes or differ between them are left out.
+func GetAddonHashes(ctx context.Context, runtime runtimes.Runtime, servers []*K3D.Node, names []string) (map[string]string, error) {
+	hashes := make(map[string]string, len(names))
+
+	for _, name := range names {
+		hash, err := getAddonHash(ctx, runtime, servers, name)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(hash) != 0 {
+			hashes[name] = hash
+		}
+	}
+
+	return hashes, nil
+}
+
+// getAddonHash returns the sha256 of the manifest of the addon when it is same in all the server nodes, and empty otherwise.
+func getAddonHash(ctx context.Context, runtime runtimes.Runtime, servers []*K3D.Node, name string) (string, error) {
+	var hash string
+
+	for index, server := range servers {
+		content, err := readFromNode(ctx, runtime, server, AddonFilePath(name))
+		if err != nil {
+			if terraformErrors.IsNotFound(terraformErrors.ClassifyLookupError("addon", name, err)) {
+				return "", nil
+			}
+
+			return "", err
+		}
+
+		serverHash := HashAddonContent(content)
+		if index != 0 && serverHash != hash {
+			return "", nil
+		}
+
+		hash = serverHash
+	}
+
+	return hash, nil
+}
+
+// readFromNode reads the file from the node, the runtime returns the file as tar archive.
+func readFromNode(ctx context.Context, runtime runtimes.Runtime, node *K3D.Node, filePath string) ([]byte, error) {
+	reader, err := runtime.ReadFromNode(ctx, filePath, node)
+	if err != nil {
+		return nil, err
+	}
+
+	defer reader.Close()
+
+	tarReader := tar.NewReader(reader)
+	if _, err = tarReader.Next(); err != nil {
+		return nil, err
+	}
+
+	return io.ReadAll(tarReader)
+}
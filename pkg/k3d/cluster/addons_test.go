@@ -0,0 +1,76 @@
+package cluster_test
+
+import (
+	"testing"
+
+	terraformErrors "github.com/nikhilsbhat/terraform-provider-k3d/pkg/errors"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/k3d/cluster"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddon_Render(t *testing.T) {
+	t.Run("should render the manifest as is", func(t *testing.T) {
+		addon := &cluster.Addon{Name: "namespace", Manifest: "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: apps\n"}
+
+		content, err := addon.Render()
+		assert.NoError(t, err)
+		assert.Equal(t, addon.Manifest, string(content))
+	})
+
+	t.Run("should render HelmChart for helm_chart", func(t *testing.T) {
+		addon := &cluster.Addon{
+			Name: "ingress-nginx",
+			HelmChart: &cluster.HelmChart{
+				Chart:           "ingress-nginx",
+				Repo:            "https://kubernetes.github.io/ingress-nginx",
+				Version:         "4.4.2",
+				TargetNamespace: "ingress",
+				Values:          "controller:\n  replicaCount: 2\n",
+			},
+		}
+
+		expected := `apiVersion: helm.cattle.io/v1
+kind: HelmChart
+metadata:
+  name: ingress-nginx
+  namespace: kube-system
+spec:
+  chart: ingress-nginx
+  repo: https://kubernetes.github.io/ingress-nginx
+  targetNamespace: ingress
+  valuesContent: |
+    controller:
+      replicaCount: 2
+  version: 4.4.2
+`
+
+		content, err := addon.Render()
+		assert.NoError(t, err)
+		assert.Equal(t, expected, string(content))
+	})
+
+	t.Run("should fail when both manifest and helm_chart are set", func(t *testing.T) {
+		addon := &cluster.Addon{Name: "invalid", Manifest: "kind: Namespace", HelmChart: &cluster.HelmChart{Chart: "traefik"}}
+
+		_, err := addon.Render()
+		assert.ErrorIs(t, err, terraformErrors.ErrInvalidAddon)
+	})
+
+	t.Run("should fail when neither manifest nor helm_chart are set", func(t *testing.T) {
+		_, err := (&cluster.Addon{Name: "invalid"}).Render()
+		assert.ErrorIs(t, err, terraformErrors.ErrInvalidAddon)
+	})
+}
+
+func TestAddon_Hash(t *testing.T) {
+	addon := &cluster.Addon{Name: "namespace", Manifest: "kind: Namespace"}
+
+	hash, err := addon.Hash()
+	assert.NoError(t, err)
+	assert.Equal(t, cluster.HashAddonContent([]byte("kind: Namespace")), hash)
+	assert.Len(t, hash, 64)
+}
+
+func TestAddonFilePath(t *testing.T) {
+	assert.Equal(t, "/var/lib/rancher/k3s/server/manifests/addon-ingress-nginx.yaml", cluster.AddonFilePath("ingress-nginx"))
+}
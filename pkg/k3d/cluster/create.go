@@ -9,14 +9,23 @@ import (
 	"github.com/rancher/k3d/v5/pkg/config"
 	"github.com/rancher/k3d/v5/pkg/config/v1alpha4"
 	"github.com/rancher/k3d/v5/pkg/runtimes"
+	K3D "github.com/rancher/k3d/v5/pkg/types"
 )
 
-func CreateCluster(ctx context.Context, runtime runtimes.Runtime, cfg *v1alpha4.SimpleConfig) error {
+// RegistryConfig holds the settings of the registry created along with the cluster, which k3d SimpleConfig does not support.
+type RegistryConfig struct {
+	Image string
+	Proxy map[string]string
+}
+
+func CreateCluster(ctx context.Context, runtime runtimes.Runtime, cfg *v1alpha4.SimpleConfig, registryCfg *RegistryConfig) error {
 	clusterConfig, err := config.TransformSimpleToClusterConfig(ctx, runtime, *cfg)
 	if err != nil {
 		return err
 	}
 
+	setRegistryConfig(clusterConfig.ClusterCreateOpts.Registries.Create, registryCfg)
+
 	clusterConfig, err = config.ProcessClusterConfig(*clusterConfig)
 	if err != nil {
 		return err
@@ -30,21 +39,22 @@ func CreateCluster(ctx context.Context, runtime runtimes.Runtime, cfg *v1alpha4.
 		return fmt.Errorf("%w: %s", terraformErrors.ErrClusterAlreadyExists, cfg.Name)
 	}
 
-	if err = client.ClusterRun(ctx, runtimes.SelectedRuntime, clusterConfig); err != nil {
-		return err
+	return client.ClusterRun(ctx, runtimes.SelectedRuntime, clusterConfig)
+}
+
+// setRegistryConfig sets the image and proxy of the registry to be created along with the cluster, if any.
+func setRegistryConfig(createRegistry *K3D.Registry, registryCfg *RegistryConfig) {
+	if createRegistry == nil || registryCfg == nil {
+		return
 	}
 
-	if clusterConfig.KubeconfigOpts.UpdateDefaultKubeconfig {
-		if _, err = client.KubeconfigGetWrite(ctx, runtimes.SelectedRuntime,
-			&clusterConfig.Cluster, "",
-			&client.WriteKubeConfigOptions{
-				UpdateExisting:       true,
-				OverwriteExisting:    false,
-				UpdateCurrentContext: cfg.Options.KubeconfigOptions.SwitchCurrentContext,
-			}); err != nil {
-			return err
-		}
+	if len(registryCfg.Image) != 0 {
+		createRegistry.Image = registryCfg.Image
 	}
 
-	return nil
+	if len(registryCfg.Proxy) != 0 {
+		createRegistry.Options.Proxy.RemoteURL = registryCfg.Proxy["remoteURL"]
+		createRegistry.Options.Proxy.Username = registryCfg.Proxy["username"]
+		createRegistry.Options.Proxy.Password = registryCfg.Proxy["password"]
+	}
 }
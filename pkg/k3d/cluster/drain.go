@@ -0,0 +1,213 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	terraformErrors "github.com/nikhilsbhat/terraform-provider-k3d/pkg/errors"
+	coreV1 "k8s.io/api/core/v1"
+	policyV1 "k8s.io/api/policy/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// mirrorPodAnnotation marks the static pods run by the kubelet, which cannot be evicted through the API.
+	mirrorPodAnnotation = "kubernetes.io/config.mirror"
+	kindDaemonSet       = "DaemonSet"
+)
+
+// DrainConfig holds how the pods running on a kubernetes node are evicted before the node is removed,
+// similar to the options of `kubectl drain`.
+type DrainConfig struct {
+	KubeClient         kubernetes.Interface
+	Timeout            time.Duration
+	Interval           time.Duration
+	IgnoreDaemonSets   bool
+	DeleteEmptyDirData bool
+	// GracePeriod is the time in seconds given to the pods to terminate, negative value uses the grace period of the pod.
+	GracePeriod int
+}
+
+// Drain cordons the kubernetes node and evicts the pods running on it, waiting until all of them are gone.
+// The evictions refused by the disruption budgets of the pods are retried until the timeout.
+// Nodes that were never registered with the cluster have nothing to be drained.
+func (cfg *DrainConfig) Drain(ctx context.Context, name string) error {
+	node, err := cfg.KubeClient.CoreV1().Nodes().Get(ctx, name, metaV1.GetOptions{})
+	if err != nil {
+		if apiErrors.IsNotFound(err) {
+			return nil
+		}
+
+		return fmt.Errorf("%w: fetching node '%s' errored with %v", terraformErrors.ErrNodeDrainFailed, name, err)
+	}
+
+	if !node.Spec.Unschedulable {
+		node.Spec.Unschedulable = true
+
+		if _, err = cfg.KubeClient.CoreV1().Nodes().Update(ctx, node, metaV1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("%w: cordoning node '%s' errored with %v", terraformErrors.ErrNodeDrainFailed, name, err)
+		}
+	}
+
+	pods, err := cfg.getPodsToEvict(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	drainCtx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+
+	for index := range pods {
+		if err = cfg.evictPod(drainCtx, &pods[index]); err != nil {
+			return fmt.Errorf("%w: evicting pod '%s/%s' from node '%s' errored with %v",
+				terraformErrors.ErrNodeDrainFailed, pods[index].Namespace, pods[index].Name, name, err)
+		}
+	}
+
+	return cfg.waitForPodsDeleted(drainCtx, name, pods)
+}
+
+// DeleteKubeNode removes the node object from the cluster, so that the node removed does not linger as NotReady.
+func DeleteKubeNode(ctx context.Context, client kubernetes.Interface, name string) error {
+	if err := client.CoreV1().Nodes().Delete(ctx, name, metaV1.DeleteOptions{}); err != nil && !apiErrors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// getPodsToEvict returns the pods on the node that have to be evicted, leaving out the mirror pods, the pods that already completed
+// and the pods of daemonsets when they are ignored. Pods of daemonsets that are not ignored and pods with emptyDir volumes
+// whose data is not allowed to be deleted fail the drain, listing all of such pods.
+func (cfg *DrainConfig) getPodsToEvict(ctx context.Context, name string) ([]coreV1.Pod, error) {
+	podList, err := cfg.KubeClient.CoreV1().Pods(metaV1.NamespaceAll).List(ctx, metaV1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", name).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: listing pods of node '%s' errored with %v", terraformErrors.ErrNodeDrainFailed, name, err)
+	}
+
+	pods := make([]coreV1.Pod, 0, len(podList.Items))
+	blocked := make([]string, 0)
+
+	for _, pod := range podList.Items {
+		if pod.Spec.NodeName != name || isPodCompleted(&pod) {
+			continue
+		}
+
+		if _, ok := pod.Annotations[mirrorPodAnnotation]; ok {
+			continue
+		}
+
+		if controller := metaV1.GetControllerOf(&pod); controller != nil && controller.Kind == kindDaemonSet {
+			if cfg.IgnoreDaemonSets {
+				continue
+			}
+
+			blocked = append(blocked, fmt.Sprintf("%s/%s (managed by daemonset)", pod.Namespace, pod.Name))
+
+			continue
+		}
+
+		if !cfg.DeleteEmptyDirData && hasEmptyDirVolume(&pod) {
+			blocked = append(blocked, fmt.Sprintf("%s/%s (uses emptyDir volume)", pod.Namespace, pod.Name))
+
+			continue
+		}
+
+		pods = append(pods, pod)
+	}
+
+	if len(blocked) != 0 {
+		return nil, fmt.Errorf("%w: node '%s' has pods that cannot be evicted: %s",
+			terraformErrors.ErrNodeDrainFailed, name, strings.Join(blocked, ", "))
+	}
+
+	return pods, nil
+}
+
+// evictPod evicts the pod through the eviction API, so that the disruption budgets of the pod are respected.
+func (cfg *DrainConfig) evictPod(ctx context.Context, pod *coreV1.Pod) error {
+	eviction := &policyV1.Eviction{
+		ObjectMeta: metaV1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+	}
+
+	if cfg.GracePeriod >= 0 {
+		gracePeriod := int64(cfg.GracePeriod)
+		eviction.DeleteOptions = &metaV1.DeleteOptions{GracePeriodSeconds: &gracePeriod}
+	}
+
+	var evictErr error
+
+	err := wait.PollImmediateUntilWithContext(ctx, cfg.Interval, func(ctx context.Context) (bool, error) {
+		evictErr = cfg.KubeClient.PolicyV1().Evictions(pod.Namespace).Evict(ctx, eviction)
+
+		switch {
+		case evictErr == nil, apiErrors.IsNotFound(evictErr):
+			return true, nil
+		case apiErrors.IsTooManyRequests(evictErr):
+			// the eviction would violate the disruption budget of the pod, hence it is retried.
+			return false, nil
+		default:
+			return false, evictErr
+		}
+	})
+	if err != nil && evictErr != nil {
+		return evictErr
+	}
+
+	return err
+}
+
+// waitForPodsDeleted waits until the pods evicted are deleted, or replaced by the pods of the same name that are not on the node.
+func (cfg *DrainConfig) waitForPodsDeleted(ctx context.Context, name string, pods []coreV1.Pod) error {
+	pending := pods
+
+	err := wait.PollImmediateUntilWithContext(ctx, cfg.Interval, func(ctx context.Context) (bool, error) {
+		remaining := make([]coreV1.Pod, 0, len(pending))
+
+		for _, pod := range pending {
+			current, err := cfg.KubeClient.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metaV1.GetOptions{})
+			if apiErrors.IsNotFound(err) || (err == nil && current.UID != pod.UID) {
+				continue
+			}
+
+			remaining = append(remaining, pod)
+		}
+
+		pending = remaining
+
+		return len(pending) == 0, nil
+	})
+	if err != nil {
+		names := make([]string, 0, len(pending))
+		for _, pod := range pending {
+			names = append(names, fmt.Sprintf("%s/%s", pod.Namespace, pod.Name))
+		}
+
+		return fmt.Errorf("%w: waiting for pods of node '%s' to be deleted errored with %v, pending: %s",
+			terraformErrors.ErrNodeDrainFailed, name, err, strings.Join(names, ", "))
+	}
+
+	return nil
+}
+
+func isPodCompleted(pod *coreV1.Pod) bool {
+	return pod.Status.Phase == coreV1.PodSucceeded || pod.Status.Phase == coreV1.PodFailed
+}
+
+func hasEmptyDirVolume(pod *coreV1.Pod) bool {
+	for _, volume := range pod.Spec.Volumes {
+		if volume.EmptyDir != nil {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,179 @@
+package cluster_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	terraformErrors "github.com/nikhilsbhat/terraform-provider-k3d/pkg/errors"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/k3d/cluster"
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	k8sTesting "k8s.io/client-go/testing"
+)
+
+func getTestPod(name, node string, mutate func(pod *coreV1.Pod)) *coreV1.Pod {
+	pod := &coreV1.Pod{
+		ObjectMeta: metaV1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID("uid-" + name)},
+		Spec:       coreV1.PodSpec{NodeName: node},
+		Status:     coreV1.PodStatus{Phase: coreV1.PodRunning},
+	}
+
+	if mutate != nil {
+		mutate(pod)
+	}
+
+	return pod
+}
+
+func withDaemonSetOwner(pod *coreV1.Pod) {
+	controller := true
+	pod.OwnerReferences = []metaV1.OwnerReference{{Kind: "DaemonSet", Name: "svclb", Controller: &controller}}
+}
+
+func withEmptyDir(pod *coreV1.Pod) {
+	pod.Spec.Volumes = []coreV1.Volume{{Name: "cache", VolumeSource: coreV1.VolumeSource{EmptyDir: &coreV1.EmptyDirVolumeSource{}}}}
+}
+
+// evictionDeletesPod makes the evictions of the fake clientset delete the pod, as the eviction API does.
+func evictionDeletesPod(client *fake.Clientset) {
+	client.PrependReactor("create", "pods", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+
+		createAction := action.(k8sTesting.CreateAction)
+		eviction := createAction.GetObject().(metaV1.Object)
+
+		return true, nil, client.Tracker().Delete(schema.GroupVersionResource{Version: "v1", Resource: "pods"},
+			eviction.GetNamespace(), eviction.GetName())
+	})
+}
+
+func TestDrainConfig_Drain(t *testing.T) {
+	drainCfg := &cluster.DrainConfig{
+		Timeout:          50 * time.Millisecond,
+		Interval:         10 * time.Millisecond,
+		IgnoreDaemonSets: true,
+		GracePeriod:      -1,
+	}
+
+	t.Run("should cordon the node and evict its pods leaving out daemonsets, mirror and completed pods", func(t *testing.T) {
+		client := fake.NewSimpleClientset(
+			getTestNode("k3d-test-agent-0", coreV1.ConditionTrue),
+			getTestPod("web", "k3d-test-agent-0", nil),
+			getTestPod("svclb", "k3d-test-agent-0", withDaemonSetOwner),
+			getTestPod("static", "k3d-test-agent-0", func(pod *coreV1.Pod) {
+				pod.Annotations = map[string]string{"kubernetes.io/config.mirror": "hash"}
+			}),
+			getTestPod("job", "k3d-test-agent-0", func(pod *coreV1.Pod) { pod.Status.Phase = coreV1.PodSucceeded }),
+			getTestPod("api", "k3d-test-agent-1", nil),
+		)
+		evictionDeletesPod(client)
+
+		cfg := *drainCfg
+		cfg.KubeClient = client
+
+		assert.NoError(t, cfg.Drain(context.Background(), "k3d-test-agent-0"))
+
+		node, err := client.CoreV1().Nodes().Get(context.Background(), "k3d-test-agent-0", metaV1.GetOptions{})
+		assert.NoError(t, err)
+		assert.True(t, node.Spec.Unschedulable)
+
+		pods, err := client.CoreV1().Pods("default").List(context.Background(), metaV1.ListOptions{})
+		assert.NoError(t, err)
+
+		names := make([]string, 0, len(pods.Items))
+		for _, pod := range pods.Items {
+			names = append(names, pod.Name)
+		}
+
+		assert.ElementsMatch(t, []string{"svclb", "static", "job", "api"}, names)
+	})
+
+	t.Run("should fail listing the pods of daemonsets and with emptyDir volumes when they are not allowed to be evicted", func(t *testing.T) {
+		client := fake.NewSimpleClientset(
+			getTestNode("k3d-test-agent-0", coreV1.ConditionTrue),
+			getTestPod("svclb", "k3d-test-agent-0", withDaemonSetOwner),
+			getTestPod("cache", "k3d-test-agent-0", withEmptyDir),
+		)
+
+		cfg := *drainCfg
+		cfg.KubeClient = client
+		cfg.IgnoreDaemonSets = false
+
+		err := cfg.Drain(context.Background(), "k3d-test-agent-0")
+		assert.ErrorIs(t, err, terraformErrors.ErrNodeDrainFailed)
+		assert.ErrorContains(t, err, "default/svclb (managed by daemonset)")
+		assert.ErrorContains(t, err, "default/cache (uses emptyDir volume)")
+	})
+
+	t.Run("should evict the pods with emptyDir volumes when their data is allowed to be deleted", func(t *testing.T) {
+		client := fake.NewSimpleClientset(
+			getTestNode("k3d-test-agent-0", coreV1.ConditionTrue),
+			getTestPod("cache", "k3d-test-agent-0", withEmptyDir),
+		)
+		evictionDeletesPod(client)
+
+		cfg := *drainCfg
+		cfg.KubeClient = client
+		cfg.DeleteEmptyDirData = true
+
+		assert.NoError(t, cfg.Drain(context.Background(), "k3d-test-agent-0"))
+	})
+
+	t.Run("should fail when the eviction is refused by the disruption budget until the timeout", func(t *testing.T) {
+		client := fake.NewSimpleClientset(
+			getTestNode("k3d-test-agent-0", coreV1.ConditionTrue),
+			getTestPod("web", "k3d-test-agent-0", nil),
+		)
+		client.PrependReactor("create", "pods", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+			return true, nil, apiErrors.NewTooManyRequests("disruption budget would be violated", 1)
+		})
+
+		cfg := *drainCfg
+		cfg.KubeClient = client
+
+		err := cfg.Drain(context.Background(), "k3d-test-agent-0")
+		assert.ErrorIs(t, err, terraformErrors.ErrNodeDrainFailed)
+		assert.ErrorContains(t, err, "default/web")
+	})
+
+	t.Run("should fail when the pods evicted are not deleted until the timeout", func(t *testing.T) {
+		client := fake.NewSimpleClientset(
+			getTestNode("k3d-test-agent-0", coreV1.ConditionTrue),
+			getTestPod("web", "k3d-test-agent-0", nil),
+		)
+
+		cfg := *drainCfg
+		cfg.KubeClient = client
+
+		err := cfg.Drain(context.Background(), "k3d-test-agent-0")
+		assert.ErrorIs(t, err, terraformErrors.ErrNodeDrainFailed)
+		assert.ErrorContains(t, err, "pending: default/web")
+	})
+
+	t.Run("should succeed when the node was never registered", func(t *testing.T) {
+		cfg := *drainCfg
+		cfg.KubeClient = fake.NewSimpleClientset()
+
+		assert.NoError(t, cfg.Drain(context.Background(), "k3d-test-agent-0"))
+	})
+}
+
+func TestDeleteKubeNode(t *testing.T) {
+	client := fake.NewSimpleClientset(getTestNode("k3d-test-agent-0", coreV1.ConditionTrue))
+
+	assert.NoError(t, cluster.DeleteKubeNode(context.Background(), client, "k3d-test-agent-0"))
+
+	_, err := client.CoreV1().Nodes().Get(context.Background(), "k3d-test-agent-0", metaV1.GetOptions{})
+	assert.True(t, apiErrors.IsNotFound(err))
+
+	assert.NoError(t, cluster.DeleteKubeNode(context.Background(), client, "k3d-test-agent-0"))
+}
@@ -0,0 +1,102 @@
+package cluster
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	terraformErrors "github.com/nikhilsbhat/terraform-provider-k3d/pkg/errors"
+	"github.com/rancher/k3d/v5/pkg/config/v1alpha4"
+)
+
+// k3s arguments set by K3sConfig.
+const (
+	K3sArgDisable        = "disable"
+	K3sArgFlannelBackend = "flannel-backend"
+	K3sArgClusterCIDR    = "cluster-cidr"
+	K3sArgServiceCIDR    = "service-cidr"
+	K3sArgClusterDNS     = "cluster-dns"
+	K3sArgClusterDomain  = "cluster-domain"
+)
+
+var (
+	// K3sComponents are the packaged components of k3s which could be disabled.
+	K3sComponents = []string{"coredns", "servicelb", "traefik", "local-storage", "metrics-server"}
+	// FlannelBackends are the backends supported by the flannel of k3s.
+	FlannelBackends = []string{"none", "vxlan", "host-gw", "wireguard-native", "ipsec", "wireguard"}
+)
+
+// K3sConfig holds the components and networking options of k3s, which are passed on to the servers as k3s arguments.
+type K3sConfig struct {
+	Disable        []string
+	FlannelBackend string
+	ClusterCIDR    string
+	ServiceCIDR    string
+	ClusterDNS     string
+	ClusterDomain  string
+}
+
+// GetK3sArgs returns the options set as the k3s arguments of the servers, since these are server only arguments of k3s.
+func (k3s *K3sConfig) GetK3sArgs() []v1alpha4.K3sArgWithNodeFilters {
+	k3sArgs := make([]v1alpha4.K3sArgWithNodeFilters, 0)
+
+	disable := append([]string{}, k3s.Disable...)
+	sort.Strings(disable)
+
+	for _, component := range disable {
+		k3sArgs = append(k3sArgs, getServerK3sArg(K3sArgDisable, component))
+	}
+
+	for _, arg := range k3s.getArgs() {
+		k3sArgs = append(k3sArgs, getServerK3sArg(arg[0], arg[1]))
+	}
+
+	return k3sArgs
+}
+
+// ValidateExtraArgs validates that the extra arguments do not set any of the k3s arguments set by K3sConfig.
+func (k3s *K3sConfig) ValidateExtraArgs(extraArgs []v1alpha4.K3sArgWithNodeFilters) error {
+	k3sArgs := make(map[string]bool)
+	if len(k3s.Disable) != 0 {
+		k3sArgs[K3sArgDisable] = true
+	}
+
+	for _, arg := range k3s.getArgs() {
+		k3sArgs[arg[0]] = true
+	}
+
+	for _, extraArg := range extraArgs {
+		key, _, _ := strings.Cut(strings.TrimLeft(extraArg.Arg, "-"), "=")
+		if k3sArgs[key] {
+			return fmt.Errorf("%w: '%s'", terraformErrors.ErrConflictingK3sArg, key)
+		}
+	}
+
+	return nil
+}
+
+// getArgs returns the k3s arguments of the options that are set, except disable.
+func (k3s *K3sConfig) getArgs() [][2]string {
+	args := make([][2]string, 0)
+
+	for _, arg := range [][2]string{
+		{K3sArgFlannelBackend, k3s.FlannelBackend},
+		{K3sArgClusterCIDR, k3s.ClusterCIDR},
+		{K3sArgServiceCIDR, k3s.ServiceCIDR},
+		{K3sArgClusterDNS, k3s.ClusterDNS},
+		{K3sArgClusterDomain, k3s.ClusterDomain},
+	} {
+		if len(arg[1]) != 0 {
+			args = append(args, arg)
+		}
+	}
+
+	return args
+}
+
+func getServerK3sArg(key, value string) v1alpha4.K3sArgWithNodeFilters {
+	return v1alpha4.K3sArgWithNodeFilters{
+		Arg:         fmt.Sprintf("--%s=%s", key, value),
+		NodeFilters: []string{"server:*"},
+	}
+}
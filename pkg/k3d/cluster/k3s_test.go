@@ -0,0 +1,52 @@
+package cluster_test
+
+import (
+	"testing"
+
+	terraformErrors "github.com/nikhilsbhat/terraform-provider-k3d/pkg/errors"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/k3d/cluster"
+	"github.com/rancher/k3d/v5/pkg/config/v1alpha4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestK3sConfig_GetK3sArgs(t *testing.T) {
+	k3s := &cluster.K3sConfig{
+		Disable:        []string{"traefik", "servicelb"},
+		FlannelBackend: "host-gw",
+		ClusterCIDR:    "10.42.0.0/16",
+		ClusterDomain:  "k3d.local",
+	}
+
+	expected := []v1alpha4.K3sArgWithNodeFilters{
+		{Arg: "--disable=servicelb", NodeFilters: []string{"server:*"}},
+		{Arg: "--disable=traefik", NodeFilters: []string{"server:*"}},
+		{Arg: "--flannel-backend=host-gw", NodeFilters: []string{"server:*"}},
+		{Arg: "--cluster-cidr=10.42.0.0/16", NodeFilters: []string{"server:*"}},
+		{Arg: "--cluster-domain=k3d.local", NodeFilters: []string{"server:*"}},
+	}
+
+	assert.Equal(t, expected, k3s.GetK3sArgs())
+	assert.Empty(t, (&cluster.K3sConfig{}).GetK3sArgs())
+}
+
+func TestK3sConfig_ValidateExtraArgs(t *testing.T) {
+	k3s := &cluster.K3sConfig{Disable: []string{"traefik"}, ServiceCIDR: "10.43.0.0/16"}
+
+	t.Run("should pass when extra args do not set the same arguments", func(t *testing.T) {
+		extraArgs := []v1alpha4.K3sArgWithNodeFilters{{Arg: "--cluster-cidr=10.42.0.0/16"}, {Arg: "--token=secret"}}
+
+		assert.NoError(t, k3s.ValidateExtraArgs(extraArgs))
+	})
+
+	t.Run("should fail when extra args disable components", func(t *testing.T) {
+		extraArgs := []v1alpha4.K3sArgWithNodeFilters{{Arg: "--disable=metrics-server", NodeFilters: []string{"server:0"}}}
+
+		assert.ErrorIs(t, k3s.ValidateExtraArgs(extraArgs), terraformErrors.ErrConflictingK3sArg)
+	})
+
+	t.Run("should fail when extra args set the service cidr", func(t *testing.T) {
+		extraArgs := []v1alpha4.K3sArgWithNodeFilters{{Arg: "--service-cidr=10.96.0.0/12"}}
+
+		assert.ErrorIs(t, k3s.ValidateExtraArgs(extraArgs), terraformErrors.ErrConflictingK3sArg)
+	})
+}
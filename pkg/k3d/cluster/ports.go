@@ -0,0 +1,87 @@
+package cluster
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/docker/go-connections/nat"
+	terraformErrors "github.com/nikhilsbhat/terraform-provider-k3d/pkg/errors"
+	"github.com/rancher/k3d/v5/pkg/config/v1alpha4"
+)
+
+const maxPort = 65535
+
+// Port maps either a port or a range of ports of the node containers to the host.
+// HostPort is left empty to let the runtime pick the host port.
+type Port struct {
+	Host          string
+	HostPort      string
+	ContainerPort string
+	Protocol      string
+	NodeFilters   []string
+}
+
+// GetPortWithNodeFilters returns the port in the format k3d expects, [HOST:][HOSTPORT:]CONTAINERPORT[/PROTOCOL].
+func (port *Port) GetPortWithNodeFilters() v1alpha4.PortWithNodeFilters {
+	spec := port.ContainerPort
+	if len(port.Host) != 0 || len(port.HostPort) != 0 {
+		spec = fmt.Sprintf("%s:%s:%s", port.Host, port.HostPort, spec)
+	}
+
+	if len(port.Protocol) != 0 {
+		spec = fmt.Sprintf("%s/%s", spec, strings.ToLower(port.Protocol))
+	}
+
+	return v1alpha4.PortWithNodeFilters{
+		Port:        spec,
+		NodeFilters: port.NodeFilters,
+	}
+}
+
+// GetPublishedHostPorts returns the host ports each of the container ports is published to, in the order of the container ports.
+// Ports that are not published, for instance when the cluster is stopped, are returned as 0.
+func (port *Port) GetPublishedHostPorts(publishedPorts nat.PortMap) ([]int, error) {
+	mappings, err := nat.ParsePortSpec(port.GetPortWithNodeFilters().Port)
+	if err != nil {
+		return nil, err
+	}
+
+	hostPorts := make([]int, 0, len(mappings))
+
+	for _, mapping := range mappings {
+		hostPort := 0
+
+		for _, binding := range publishedPorts[mapping.Port] {
+			if len(port.Host) != 0 && binding.HostIP != port.Host {
+				continue
+			}
+
+			if len(mapping.Binding.HostPort) != 0 && mapping.Binding.HostPort != "0" && binding.HostPort != mapping.Binding.HostPort {
+				continue
+			}
+
+			if hostPort, err = strconv.Atoi(binding.HostPort); err == nil {
+				break
+			}
+		}
+
+		hostPorts = append(hostPorts, hostPort)
+	}
+
+	return hostPorts, nil
+}
+
+// ValidatePortRange validates either a port or a range of ports, ex: 80 or 30000-30010.
+func ValidatePortRange(portRange string) error {
+	start, end, err := nat.ParsePortRangeToInt(portRange)
+	if err != nil {
+		return fmt.Errorf("%w: %v", terraformErrors.ErrInvalidPortRange, err)
+	}
+
+	if start < 1 || end > maxPort {
+		return fmt.Errorf("%w: '%s' should be within 1-%d", terraformErrors.ErrInvalidPortRange, portRange, maxPort)
+	}
+
+	return nil
+}
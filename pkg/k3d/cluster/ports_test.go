@@ -0,0 +1,61 @@
+package cluster_test
+
+import (
+	"testing"
+
+	"github.com/docker/go-connections/nat"
+	terraformErrors "github.com/nikhilsbhat/terraform-provider-k3d/pkg/errors"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/k3d/cluster"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPort_GetPortWithNodeFilters(t *testing.T) {
+	for expected, port := range map[string]*cluster.Port{
+		"80":                       {ContainerPort: "80"},
+		"0.0.0.0:8080:80/tcp":      {Host: "0.0.0.0", HostPort: "8080", ContainerPort: "80", Protocol: "TCP"},
+		"127.0.0.1::80/udp":        {Host: "127.0.0.1", ContainerPort: "80", Protocol: "udp"},
+		":30000-30010:30000-30010": {HostPort: "30000-30010", ContainerPort: "30000-30010"},
+	} {
+		assert.Equal(t, expected, port.GetPortWithNodeFilters().Port)
+	}
+}
+
+func TestPort_GetPublishedHostPorts(t *testing.T) {
+	publishedPorts := nat.PortMap{
+		"80/tcp":    {{HostIP: "0.0.0.0", HostPort: "8080"}, {HostIP: "0.0.0.0", HostPort: "49153"}},
+		"30000/tcp": {{HostIP: "0.0.0.0", HostPort: "30000"}},
+		"30001/tcp": {{HostIP: "0.0.0.0", HostPort: "30001"}},
+	}
+
+	t.Run("should return the host port that was set", func(t *testing.T) {
+		hostPorts, err := (&cluster.Port{HostPort: "8080", ContainerPort: "80"}).GetPublishedHostPorts(publishedPorts)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{8080}, hostPorts)
+	})
+
+	t.Run("should return the host port picked by the runtime", func(t *testing.T) {
+		hostPorts, err := (&cluster.Port{ContainerPort: "80", Protocol: "TCP"}).GetPublishedHostPorts(publishedPorts)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{8080}, hostPorts)
+	})
+
+	t.Run("should return the host ports of the range", func(t *testing.T) {
+		hostPorts, err := (&cluster.Port{HostPort: "30000-30002", ContainerPort: "30000-30002"}).GetPublishedHostPorts(publishedPorts)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{30000, 30001, 0}, hostPorts)
+	})
+
+	t.Run("should fail when the ranges differ in length", func(t *testing.T) {
+		_, err := (&cluster.Port{HostPort: "30000-30001", ContainerPort: "30000-30002"}).GetPublishedHostPorts(publishedPorts)
+		assert.Error(t, err)
+	})
+}
+
+func TestValidatePortRange(t *testing.T) {
+	assert.NoError(t, cluster.ValidatePortRange("80"))
+	assert.NoError(t, cluster.ValidatePortRange("30000-30010"))
+
+	for _, portRange := range []string{"", "0", "http", "30010-30000", "65536"} {
+		assert.ErrorIs(t, cluster.ValidatePortRange(portRange), terraformErrors.ErrInvalidPortRange, portRange)
+	}
+}
@@ -0,0 +1,234 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	terraformErrors "github.com/nikhilsbhat/terraform-provider-k3d/pkg/errors"
+	appsV1 "k8s.io/api/apps/v1"
+	coreV1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+const (
+	WorkloadDeployment = "deployment"
+	WorkloadDaemonSet  = "daemonset"
+)
+
+// Workload identifies the deployment or daemonset which has to be available before the cluster is considered ready.
+type Workload struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Timeout   time.Duration
+}
+
+// ReadinessConfig holds the kubernetes objects to be waited for before the cluster is considered ready.
+type ReadinessConfig struct {
+	Nodes        int
+	NodesTimeout time.Duration
+	Workloads    []Workload
+	Interval     time.Duration
+}
+
+// NewKubeClient returns the client of the kubernetes cluster the kubeconfig points to.
+func NewKubeClient(kubeConfig *api.Config) (kubernetes.Interface, error) {
+	restConfig, err := clientcmd.NewDefaultClientConfig(*kubeConfig, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return kubernetes.NewForConfig(restConfig)
+}
+
+// Wait waits for the nodes to be Ready and then for every workload to be Available, each within its own timeout.
+func (cfg *ReadinessConfig) Wait(ctx context.Context, client kubernetes.Interface) error {
+	if cfg.Nodes != 0 {
+		if err := WaitForNodesReady(ctx, client, cfg.Nodes, cfg.NodesTimeout, cfg.Interval); err != nil {
+			return err
+		}
+	}
+
+	for _, workload := range cfg.Workloads {
+		if err := WaitForWorkloadAvailable(ctx, client, workload, cfg.Interval); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WaitForNodesReady waits until the cluster has at least the expected number of nodes and all of them are Ready.
+func WaitForNodesReady(ctx context.Context, client kubernetes.Interface, expected int, timeout, interval time.Duration) error {
+	var notReady string
+
+	err := wait.PollImmediateWithContext(ctx, interval, timeout, func(ctx context.Context) (bool, error) {
+		nodes, err := client.CoreV1().Nodes().List(ctx, metaV1.ListOptions{})
+		if err != nil {
+			notReady = err.Error()
+
+			return false, nil //nolint:nilerr
+		}
+
+		if len(nodes.Items) < expected {
+			notReady = fmt.Sprintf("%d of %d nodes registered", len(nodes.Items), expected)
+
+			return false, nil
+		}
+
+		for _, node := range nodes.Items {
+			if !isNodeReady(&node) {
+				notReady = fmt.Sprintf("node '%s' is not Ready", node.Name)
+
+				return false, nil
+			}
+		}
+
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("%w: waiting for nodes errored with %v, %s", terraformErrors.ErrClusterNotReady, err, notReady)
+	}
+
+	return nil
+}
+
+// WaitForNodeReady waits until the node is Ready as reported by its kubelet after since, so that the status reported
+// before the container of the node was replaced is not considered.
+func WaitForNodeReady(ctx context.Context, client kubernetes.Interface, name string, since time.Time, timeout, interval time.Duration) error {
+	notReady := "not registered"
+	since = since.Truncate(time.Second)
+
+	err := wait.PollImmediateWithContext(ctx, interval, timeout, func(ctx context.Context) (bool, error) {
+		node, err := client.CoreV1().Nodes().Get(ctx, name, metaV1.GetOptions{})
+		if err != nil {
+			notReady = err.Error()
+
+			return false, nil //nolint:nilerr
+		}
+
+		for _, condition := range node.Status.Conditions {
+			if condition.Type != coreV1.NodeReady {
+				continue
+			}
+
+			if condition.LastHeartbeatTime.Time.Before(since) {
+				notReady = "status is not reported since the node was replaced"
+
+				return false, nil
+			}
+
+			notReady = fmt.Sprintf("Ready condition is %s", condition.Status)
+
+			return condition.Status == coreV1.ConditionTrue, nil
+		}
+
+		notReady = "Ready condition is not reported"
+
+		return false, nil
+	})
+	if err != nil {
+		return fmt.Errorf("%w: waiting for node '%s' errored with %v, %s", terraformErrors.ErrClusterNotReady, name, err, notReady)
+	}
+
+	return nil
+}
+
+// WaitForWorkloadAvailable waits until the deployment or daemonset exists and all of its replicas are updated and Available.
+func WaitForWorkloadAvailable(ctx context.Context, client kubernetes.Interface, workload Workload, interval time.Duration) error {
+	if workload.Kind != WorkloadDeployment && workload.Kind != WorkloadDaemonSet {
+		return fmt.Errorf("%w: %s", terraformErrors.ErrUnsupportedWorkload, workload.Kind)
+	}
+
+	var notReady string
+
+	err := wait.PollImmediateWithContext(ctx, interval, workload.Timeout, func(ctx context.Context) (bool, error) {
+		available, state, err := isWorkloadAvailable(ctx, client, workload)
+		if err != nil {
+			if !apiErrors.IsNotFound(err) {
+				return false, err
+			}
+
+			state = "not found"
+		}
+
+		notReady = state
+
+		return available, nil
+	})
+	if err != nil {
+		return fmt.Errorf("%w: waiting for %s '%s/%s' errored with %v, %s", terraformErrors.ErrClusterNotReady,
+			workload.Kind, workload.Namespace, workload.Name, err, notReady)
+	}
+
+	return nil
+}
+
+func isWorkloadAvailable(ctx context.Context, client kubernetes.Interface, workload Workload) (bool, string, error) {
+	if workload.Kind == WorkloadDaemonSet {
+		daemonSet, err := client.AppsV1().DaemonSets(workload.Namespace).Get(ctx, workload.Name, metaV1.GetOptions{})
+		if err != nil {
+			return false, "", err
+		}
+
+		available, state := isDaemonSetAvailable(daemonSet)
+
+		return available, state, nil
+	}
+
+	deployment, err := client.AppsV1().Deployments(workload.Namespace).Get(ctx, workload.Name, metaV1.GetOptions{})
+	if err != nil {
+		return false, "", err
+	}
+
+	available, state := isDeploymentAvailable(deployment)
+
+	return available, state, nil
+}
+
+func isNodeReady(node *coreV1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == coreV1.NodeReady {
+			return condition.Status == coreV1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+func isDeploymentAvailable(deployment *appsV1.Deployment) (bool, string) {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+
+	if deployment.Status.ObservedGeneration < deployment.Generation {
+		return false, "latest generation is not observed yet"
+	}
+
+	if deployment.Status.UpdatedReplicas < replicas || deployment.Status.AvailableReplicas < replicas {
+		return false, fmt.Sprintf("%d of %d replicas available", deployment.Status.AvailableReplicas, replicas)
+	}
+
+	return true, ""
+}
+
+func isDaemonSetAvailable(daemonSet *appsV1.DaemonSet) (bool, string) {
+	desired := daemonSet.Status.DesiredNumberScheduled
+
+	if daemonSet.Status.ObservedGeneration < daemonSet.Generation {
+		return false, "latest generation is not observed yet"
+	}
+
+	if daemonSet.Status.UpdatedNumberScheduled < desired || daemonSet.Status.NumberAvailable < desired {
+		return false, fmt.Sprintf("%d of %d pods available", daemonSet.Status.NumberAvailable, desired)
+	}
+
+	return true, ""
+}
@@ -0,0 +1,144 @@
+package cluster_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	terraformErrors "github.com/nikhilsbhat/terraform-provider-k3d/pkg/errors"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/k3d/cluster"
+	"github.com/stretchr/testify/assert"
+	appsV1 "k8s.io/api/apps/v1"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func getTestNode(name string, ready coreV1.ConditionStatus) *coreV1.Node {
+	return &coreV1.Node{
+		ObjectMeta: metaV1.ObjectMeta{Name: name},
+		Status: coreV1.NodeStatus{Conditions: []coreV1.NodeCondition{
+			{Type: coreV1.NodeReady, Status: ready},
+		}},
+	}
+}
+
+func getTestDeployment(available int32) *appsV1.Deployment {
+	replicas := int32(1)
+
+	return &appsV1.Deployment{
+		ObjectMeta: metaV1.ObjectMeta{Name: "coredns", Namespace: "kube-system"},
+		Spec:       appsV1.DeploymentSpec{Replicas: &replicas},
+		Status:     appsV1.DeploymentStatus{UpdatedReplicas: 1, AvailableReplicas: available},
+	}
+}
+
+func TestReadinessConfig_Wait(t *testing.T) {
+	readinessCfg := &cluster.ReadinessConfig{
+		Nodes:        2,
+		NodesTimeout: 50 * time.Millisecond,
+		Interval:     10 * time.Millisecond,
+		Workloads: []cluster.Workload{
+			{Kind: cluster.WorkloadDeployment, Namespace: "kube-system", Name: "coredns", Timeout: 50 * time.Millisecond},
+		},
+	}
+
+	t.Run("should succeed when nodes are ready and workloads are available", func(t *testing.T) {
+		client := fake.NewSimpleClientset(
+			getTestNode("k3d-test-server-0", coreV1.ConditionTrue),
+			getTestNode("k3d-test-agent-0", coreV1.ConditionTrue),
+			getTestDeployment(1),
+		)
+
+		assert.NoError(t, readinessCfg.Wait(context.Background(), client))
+	})
+
+	t.Run("should fail when a node is not ready", func(t *testing.T) {
+		client := fake.NewSimpleClientset(
+			getTestNode("k3d-test-server-0", coreV1.ConditionTrue),
+			getTestNode("k3d-test-agent-0", coreV1.ConditionFalse),
+			getTestDeployment(1),
+		)
+
+		err := readinessCfg.Wait(context.Background(), client)
+		assert.ErrorIs(t, err, terraformErrors.ErrClusterNotReady)
+		assert.ErrorContains(t, err, "k3d-test-agent-0")
+	})
+
+	t.Run("should fail when fewer nodes are registered", func(t *testing.T) {
+		client := fake.NewSimpleClientset(getTestNode("k3d-test-server-0", coreV1.ConditionTrue))
+
+		err := readinessCfg.Wait(context.Background(), client)
+		assert.ErrorIs(t, err, terraformErrors.ErrClusterNotReady)
+		assert.ErrorContains(t, err, "1 of 2 nodes registered")
+	})
+
+	t.Run("should fail when deployment is not available", func(t *testing.T) {
+		client := fake.NewSimpleClientset(
+			getTestNode("k3d-test-server-0", coreV1.ConditionTrue),
+			getTestNode("k3d-test-agent-0", coreV1.ConditionTrue),
+			getTestDeployment(0),
+		)
+
+		err := readinessCfg.Wait(context.Background(), client)
+		assert.ErrorIs(t, err, terraformErrors.ErrClusterNotReady)
+		assert.ErrorContains(t, err, "0 of 1 replicas available")
+	})
+}
+
+func TestWaitForNodeReady(t *testing.T) {
+	since := time.Now()
+
+	getReportedNode := func(ready coreV1.ConditionStatus, heartbeat time.Time) *coreV1.Node {
+		node := getTestNode("k3d-test-server-0", ready)
+		node.Status.Conditions[0].LastHeartbeatTime = metaV1.NewTime(heartbeat)
+
+		return node
+	}
+
+	t.Run("should succeed when node is reported Ready after it was replaced", func(t *testing.T) {
+		client := fake.NewSimpleClientset(getReportedNode(coreV1.ConditionTrue, since.Add(time.Second)))
+
+		assert.NoError(t, cluster.WaitForNodeReady(context.Background(), client, "k3d-test-server-0", since, 50*time.Millisecond, 10*time.Millisecond))
+	})
+
+	t.Run("should fail when Ready was reported before the node was replaced", func(t *testing.T) {
+		client := fake.NewSimpleClientset(getReportedNode(coreV1.ConditionTrue, since.Add(-time.Minute)))
+
+		err := cluster.WaitForNodeReady(context.Background(), client, "k3d-test-server-0", since, 50*time.Millisecond, 10*time.Millisecond)
+		assert.ErrorIs(t, err, terraformErrors.ErrClusterNotReady)
+		assert.ErrorContains(t, err, "status is not reported since the node was replaced")
+	})
+
+	t.Run("should fail when node is not Ready", func(t *testing.T) {
+		client := fake.NewSimpleClientset(getReportedNode(coreV1.ConditionFalse, since.Add(time.Second)))
+
+		err := cluster.WaitForNodeReady(context.Background(), client, "k3d-test-server-0", since, 50*time.Millisecond, 10*time.Millisecond)
+		assert.ErrorIs(t, err, terraformErrors.ErrClusterNotReady)
+		assert.ErrorContains(t, err, "Ready condition is False")
+	})
+}
+
+func TestWaitForWorkloadAvailable(t *testing.T) {
+	t.Run("should wait for daemonset to be created and available", func(t *testing.T) {
+		client := fake.NewSimpleClientset()
+
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+
+			_, _ = client.AppsV1().DaemonSets("kube-system").Create(context.Background(), &appsV1.DaemonSet{
+				ObjectMeta: metaV1.ObjectMeta{Name: "svclb-traefik", Namespace: "kube-system"},
+				Status:     appsV1.DaemonSetStatus{DesiredNumberScheduled: 2, UpdatedNumberScheduled: 2, NumberAvailable: 2},
+			}, metaV1.CreateOptions{})
+		}()
+
+		workload := cluster.Workload{Kind: cluster.WorkloadDaemonSet, Namespace: "kube-system", Name: "svclb-traefik", Timeout: time.Second}
+		assert.NoError(t, cluster.WaitForWorkloadAvailable(context.Background(), client, workload, 10*time.Millisecond))
+	})
+
+	t.Run("should fail for unsupported workload", func(t *testing.T) {
+		workload := cluster.Workload{Kind: "statefulset", Namespace: "default", Name: "db", Timeout: time.Second}
+		err := cluster.WaitForWorkloadAvailable(context.Background(), fake.NewSimpleClientset(), workload, 10*time.Millisecond)
+		assert.ErrorIs(t, err, terraformErrors.ErrUnsupportedWorkload)
+	})
+}
@@ -0,0 +1,128 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	terraformErrors "github.com/nikhilsbhat/terraform-provider-k3d/pkg/errors"
+	"github.com/rancher/k3d/v5/pkg/runtimes"
+	K3D "github.com/rancher/k3d/v5/pkg/types"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// etcdNodeRoleLabel is set by k3s on the kubernetes nodes of the servers that are members of embedded etcd.
+	etcdNodeRoleLabel         = "node-role.kubernetes.io/etcd"
+	etcdMemberRemovalTimeout  = 2 * time.Minute
+	etcdMemberRemovalInterval = 2 * time.Second
+)
+
+// HasEmbeddedEtcd reports whether the servers of the specified cluster were initialised with embedded etcd (--cluster-init).
+func HasEmbeddedEtcd(ctx context.Context, runtime runtimes.Runtime, cluster string) (bool, error) {
+	servers, err := runtime.GetNodesByLabel(ctx, map[string]string{
+		K3D.LabelClusterName: cluster,
+		K3D.LabelRole:        string(K3D.ServerRole),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	for _, server := range servers {
+		if server.RuntimeLabels[K3D.LabelServerIsInit] == "true" {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// GetEtcdMembers returns the count of the members of embedded etcd, as the kubernetes nodes of the servers.
+// k3s removes a server from the etcd members only when its kubernetes node is deleted, hence the servers whose containers
+// were removed without it are still counted as members.
+func GetEtcdMembers(ctx context.Context, client kubernetes.Interface) (int, error) {
+	nodes, err := client.CoreV1().Nodes().List(ctx, metaV1.ListOptions{LabelSelector: etcdNodeRoleLabel + "=true"})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(nodes.Items), nil
+}
+
+// RemoveEtcdMember deletes the kubernetes node of the server and waits until it is gone, k3s removes the server
+// from the members of embedded etcd before the node is deleted. The server has to be removed from etcd before
+// its container is deleted, otherwise it stays as a member and counts against the quorum.
+func RemoveEtcdMember(ctx context.Context, client kubernetes.Interface, name string) error {
+	if err := DeleteKubeNode(ctx, client, name); err != nil {
+		return fmt.Errorf("removing kubernetes node of server '%s' errored with: %w", name, err)
+	}
+
+	removalCtx, cancel := context.WithTimeout(ctx, etcdMemberRemovalTimeout)
+	defer cancel()
+
+	err := wait.PollImmediateUntilWithContext(removalCtx, etcdMemberRemovalInterval, func(ctx context.Context) (bool, error) {
+		_, err := client.CoreV1().Nodes().Get(ctx, name, metaV1.GetOptions{})
+		if apiErrors.IsNotFound(err) {
+			return true, nil
+		}
+
+		return false, err
+	})
+	if err != nil {
+		return fmt.Errorf("waiting for server '%s' to be removed from etcd errored with: %w", name, err)
+	}
+
+	return nil
+}
+
+// ValidateServerScaling validates whether servers of a cluster can be scaled from current to desired count
+// without breaking the embedded etcd quorum.
+// The servers are removed from etcd before their containers are deleted, but the members left behind by the servers removed
+// otherwise are still counted, hence the servers left running should hold majority of the members left.
+func ValidateServerScaling(members, current, desired int, embeddedEtcd bool) error {
+	if desired < 1 {
+		return terraformErrors.ErrMinimumServers
+	}
+
+	if desired == current {
+		return nil
+	}
+
+	if desired > current {
+		if !embeddedEtcd {
+			return terraformErrors.ErrNoEmbeddedEtcd
+		}
+
+		return nil
+	}
+
+	// every server running is a member of etcd, even when its kubernetes node is yet to be registered.
+	if members < current {
+		members = current
+	}
+
+	if membersLeft := members - (current - desired); desired <= membersLeft/2 { //nolint:gomnd
+		return fmt.Errorf("%w: %d of %d etcd members would be left running", terraformErrors.ErrEtcdQuorumLost, desired, membersLeft)
+	}
+
+	return nil
+}
+
+// ValidateServerQuorum validates whether the servers of a cluster can be scaled from current to desired count like ValidateServerScaling,
+// and additionally blocks the additions and removals that would leave the cluster with an even number of servers, as embedded etcd
+// tolerates no more failures with an even number of members than with one member less. Additions are blocked as well, since the
+// servers added could not be removed afterwards without breaking the same rule.
+func ValidateServerQuorum(members, current, desired int, embeddedEtcd bool) error {
+	if err := ValidateServerScaling(members, current, desired, embeddedEtcd); err != nil {
+		return err
+	}
+
+	if desired != current && desired%2 == 0 {
+		return fmt.Errorf("%w: scaling from %d to %d servers", terraformErrors.ErrEvenServers, current, desired)
+	}
+
+	return nil
+}
@@ -0,0 +1,203 @@
+package cluster_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	terraformErrors "github.com/nikhilsbhat/terraform-provider-k3d/pkg/errors"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/k3d/cluster"
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestValidateServerScaling(t *testing.T) {
+	tests := []struct {
+		name         string
+		members      int
+		current      int
+		desired      int
+		embeddedEtcd bool
+		wantErr      error
+	}{
+		{
+			name:         "should allow adding servers to cluster with embedded etcd",
+			current:      3,
+			desired:      5,
+			embeddedEtcd: true,
+		},
+		{
+			name:    "should not allow adding servers to cluster without embedded etcd",
+			current: 1,
+			desired: 3,
+			wantErr: terraformErrors.ErrNoEmbeddedEtcd,
+		},
+		{
+			name:         "should allow removing a server when majority is left",
+			current:      3,
+			desired:      2,
+			embeddedEtcd: true,
+		},
+		{
+			name:         "should allow removing servers one at a time from etcd down to one server",
+			members:      3,
+			current:      3,
+			desired:      1,
+			embeddedEtcd: true,
+		},
+		{
+			name:         "should not allow removing servers when majority of the members left is lost",
+			members:      5,
+			current:      3,
+			desired:      2,
+			embeddedEtcd: true,
+			wantErr:      terraformErrors.ErrEtcdQuorumLost,
+		},
+		{
+			name:         "should not allow removing servers when half of the members left are not running",
+			members:      6,
+			current:      4,
+			desired:      2,
+			embeddedEtcd: true,
+			wantErr:      terraformErrors.ErrEtcdQuorumLost,
+		},
+		{
+			name:         "should not allow removing all servers",
+			current:      1,
+			desired:      0,
+			embeddedEtcd: false,
+			wantErr:      terraformErrors.ErrMinimumServers,
+		},
+		{
+			name:    "should allow when there is nothing to scale",
+			current: 1,
+			desired: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := cluster.ValidateServerScaling(tt.members, tt.current, tt.desired, tt.embeddedEtcd)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestValidateServerQuorum(t *testing.T) {
+	tests := []struct {
+		name         string
+		current      int
+		desired      int
+		embeddedEtcd bool
+		wantErr      error
+	}{
+		{
+			name:         "should allow removing servers when an odd number of them is left with quorum",
+			current:      5,
+			desired:      3,
+			embeddedEtcd: true,
+		},
+		{
+			name:         "should not allow removing a server when an even number of them is left",
+			current:      3,
+			desired:      2,
+			embeddedEtcd: true,
+			wantErr:      terraformErrors.ErrEvenServers,
+		},
+		{
+			name:         "should not allow removing all servers",
+			current:      3,
+			desired:      0,
+			embeddedEtcd: true,
+			wantErr:      terraformErrors.ErrMinimumServers,
+		},
+		{
+			name:         "should allow adding servers when an odd number of them would be running",
+			current:      1,
+			desired:      3,
+			embeddedEtcd: true,
+		},
+		{
+			name:         "should not allow adding a server when an even number of them would be running",
+			current:      1,
+			desired:      2,
+			embeddedEtcd: true,
+			wantErr:      terraformErrors.ErrEvenServers,
+		},
+		{
+			name:    "should not allow adding servers to cluster without embedded etcd",
+			current: 1,
+			desired: 2,
+			wantErr: terraformErrors.ErrNoEmbeddedEtcd,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := cluster.ValidateServerQuorum(tt.current, tt.current, tt.desired, tt.embeddedEtcd)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func getTestEtcdNode(name string) *coreV1.Node {
+	return &coreV1.Node{ObjectMeta: metaV1.ObjectMeta{Name: name, Labels: map[string]string{"node-role.kubernetes.io/etcd": "true"}}}
+}
+
+func TestScaleServersWithEtcdMembers(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(getTestEtcdNode("k3d-test-server-0"), getTestEtcdNode("k3d-test-server-1"),
+		getTestEtcdNode("k3d-test-server-2"), &coreV1.Node{ObjectMeta: metaV1.ObjectMeta{Name: "k3d-test-agent-0"}})
+
+	// scale scales the servers from current to desired count the way the servers of the cluster are scaled,
+	// validating the scaling against the members of etcd before removing them.
+	scale := func(current, desired int) {
+		members, err := cluster.GetEtcdMembers(ctx, client)
+		assert.NoError(t, err)
+
+		assert.NoError(t, cluster.ValidateServerScaling(members, current, desired, true))
+
+		for index := current - 1; index >= desired; index-- {
+			assert.NoError(t, cluster.RemoveEtcdMember(ctx, client, fmt.Sprintf("k3d-test-server-%d", index)))
+		}
+
+		for index := current; index < desired; index++ {
+			_, err = client.CoreV1().Nodes().Create(ctx, getTestEtcdNode(fmt.Sprintf("k3d-test-server-%d", index)), metaV1.CreateOptions{})
+			assert.NoError(t, err)
+		}
+
+		members, err = cluster.GetEtcdMembers(ctx, client)
+		assert.NoError(t, err)
+		assert.Equal(t, desired, members)
+	}
+
+	scale(3, 2)
+	scale(2, 3)
+	scale(3, 2)
+
+	t.Run("should count the members of the servers whose containers were removed without removing them from etcd", func(t *testing.T) {
+		_, err := client.CoreV1().Nodes().Create(ctx, getTestEtcdNode("k3d-test-server-2"), metaV1.CreateOptions{})
+		assert.NoError(t, err)
+
+		members, err := cluster.GetEtcdMembers(ctx, client)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, members)
+
+		// only two of the three members are running, removing one more of them loses the quorum.
+		assert.ErrorIs(t, cluster.ValidateServerScaling(members, 2, 1, true), terraformErrors.ErrEtcdQuorumLost)
+	})
+}
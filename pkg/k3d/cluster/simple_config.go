@@ -0,0 +1,140 @@
+package cluster
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	terraformErrors "github.com/nikhilsbhat/terraform-provider-k3d/pkg/errors"
+	"github.com/rancher/k3d/v5/pkg/config"
+	"github.com/rancher/k3d/v5/pkg/config/v1alpha4"
+	"github.com/spf13/viper"
+	"github.com/xeipuuv/gojsonschema"
+	"sigs.k8s.io/yaml"
+)
+
+const simpleConfigKind = "Simple"
+
+// LoadSimpleConfig reads the k3d SimpleConfig either from the path to the config file or from the inline YAML passed,
+// configs of older apiVersion are migrated to k3d.io/v1alpha4.
+func LoadSimpleConfig(simpleConfig string) (*v1alpha4.SimpleConfig, error) {
+	content, err := readSimpleConfig(simpleConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	cfgViper := viper.New()
+	cfgViper.SetConfigType("yaml")
+	// same as the default of `k3d cluster create --wait`.
+	cfgViper.SetDefault("options.k3d.wait", true)
+
+	if err = cfgViper.ReadConfig(bytes.NewReader(content)); err != nil {
+		return nil, fmt.Errorf("%w: %v", terraformErrors.ErrInvalidSimpleConfig, err)
+	}
+
+	if kind := cfgViper.GetString("kind"); len(kind) != 0 && !strings.EqualFold(kind, simpleConfigKind) {
+		return nil, fmt.Errorf("%w: %s", terraformErrors.ErrUnsupportedKind, kind)
+	}
+
+	cfg, err := config.SimpleConfigFromViper(cfgViper)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", terraformErrors.ErrInvalidSimpleConfig, err)
+	}
+
+	return &cfg, nil
+}
+
+// ValidateSimpleConfig validates the k3d SimpleConfig against the JSON schema of its apiVersion,
+// an error is returned for every field that is invalid.
+func ValidateSimpleConfig(simpleConfig string) []error {
+	content, err := readSimpleConfig(simpleConfig)
+	if err != nil {
+		return []error{err}
+	}
+
+	var document map[string]any
+	if err = yaml.Unmarshal(content, &document); err != nil {
+		return []error{fmt.Errorf("%w: %v", terraformErrors.ErrInvalidSimpleConfig, err)}
+	}
+
+	if document == nil {
+		document = make(map[string]any)
+	}
+
+	if _, ok := document["apiVersion"]; !ok {
+		document["apiVersion"] = config.DefaultConfigApiVersion
+	}
+
+	if _, ok := document["kind"]; !ok {
+		document["kind"] = simpleConfigKind
+	}
+
+	if kind, ok := document["kind"].(string); !ok || !strings.EqualFold(kind, simpleConfigKind) {
+		return []error{fmt.Errorf("%w: %v", terraformErrors.ErrUnsupportedKind, document["kind"])}
+	}
+
+	schema, err := config.GetSchemaByVersion(fmt.Sprintf("%v", document["apiVersion"]))
+	if err != nil {
+		return []error{fmt.Errorf("%w: %v", terraformErrors.ErrInvalidSimpleConfig, err)}
+	}
+
+	result, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(schema), gojsonschema.NewGoLoader(document))
+	if err != nil {
+		return []error{fmt.Errorf("%w: %v", terraformErrors.ErrInvalidSimpleConfig, err)}
+	}
+
+	validationErrors := make([]error, 0, len(result.Errors()))
+	for _, resultErr := range result.Errors() {
+		validationErrors = append(validationErrors, fmt.Errorf("%w: field '%s': %s",
+			terraformErrors.ErrInvalidSimpleConfig, resultErr.Field(), resultErr.Description()))
+	}
+
+	return validationErrors
+}
+
+// MergeSimpleConfig merges the k3d SimpleConfig with the one built from the terraform attributes,
+// the fields set in the latter overrides the matching fields of the SimpleConfig.
+func MergeSimpleConfig(overrides, simpleConfig *v1alpha4.SimpleConfig) (*v1alpha4.SimpleConfig, error) {
+	return config.MergeSimple(*overrides, *simpleConfig)
+}
+
+// HashSimpleConfig returns the sha256 of the contents of the config, read from the file when the config points to one,
+// so that the changes to the file are detected while its path is unchanged.
+func HashSimpleConfig(simpleConfig string) (string, error) {
+	content, err := readSimpleConfig(simpleConfig)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(content)
+
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// readSimpleConfig reads the contents of the file if the config points to an existing file,
+// else the config is considered as inline YAML.
+func readSimpleConfig(simpleConfig string) ([]byte, error) {
+	if info, err := os.Stat(simpleConfig); err == nil && !info.IsDir() {
+		return os.ReadFile(simpleConfig)
+	}
+
+	return []byte(simpleConfig), nil
+}
+
+// MarshalSimpleConfig renders the k3d SimpleConfig as YAML of the default apiVersion,
+// which could be passed on to simple_config or to `k3d cluster create --config`.
+func MarshalSimpleConfig(simpleConfig *v1alpha4.SimpleConfig) (string, error) {
+	cfg := *simpleConfig
+	cfg.APIVersion = config.DefaultConfigApiVersion
+	cfg.Kind = simpleConfigKind
+
+	content, err := yaml.Marshal(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(content), nil
+}
@@ -0,0 +1,146 @@
+package cluster_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	terraformErrors "github.com/nikhilsbhat/terraform-provider-k3d/pkg/errors"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/k3d/cluster"
+	"github.com/rancher/k3d/v5/pkg/config/types"
+	"github.com/rancher/k3d/v5/pkg/config/v1alpha4"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSimpleConfig = `apiVersion: k3d.io/v1alpha4
+kind: Simple
+metadata:
+  name: from-config
+servers: 3
+agents: 2
+image: rancher/k3s:v1.24.4-k3s1
+options:
+  k3d:
+    wait: true
+    timeout: 60s
+`
+
+func TestLoadSimpleConfig(t *testing.T) {
+	t.Run("should load the config from inline yaml", func(t *testing.T) {
+		cfg, err := cluster.LoadSimpleConfig(testSimpleConfig)
+		assert.NoError(t, err)
+		assert.Equal(t, "from-config", cfg.Name)
+		assert.Equal(t, 3, cfg.Servers)
+		assert.Equal(t, 2, cfg.Agents)
+		assert.Equal(t, time.Minute, cfg.Options.K3dOptions.Timeout)
+	})
+
+	t.Run("should load the config from file", func(t *testing.T) {
+		configFile := filepath.Join(t.TempDir(), "config.yaml")
+		assert.NoError(t, os.WriteFile(configFile, []byte(testSimpleConfig), 0o600))
+
+		cfg, err := cluster.LoadSimpleConfig(configFile)
+		assert.NoError(t, err)
+		assert.Equal(t, "from-config", cfg.Name)
+		assert.Equal(t, "rancher/k3s:v1.24.4-k3s1", cfg.Image)
+	})
+
+	t.Run("should wait for the cluster when the config does not say otherwise", func(t *testing.T) {
+		cfg, err := cluster.LoadSimpleConfig("servers: 1\n")
+		assert.NoError(t, err)
+		assert.True(t, cfg.Options.K3dOptions.Wait)
+
+		cfg, err = cluster.LoadSimpleConfig("options:\n  k3d:\n    wait: false\n")
+		assert.NoError(t, err)
+		assert.False(t, cfg.Options.K3dOptions.Wait)
+	})
+
+	t.Run("should fail to load config of kind other than Simple", func(t *testing.T) {
+		_, err := cluster.LoadSimpleConfig("apiVersion: k3d.io/v1alpha4\nkind: Cluster\n")
+		assert.ErrorIs(t, err, terraformErrors.ErrUnsupportedKind)
+	})
+}
+
+func TestHashSimpleConfig(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, os.WriteFile(configFile, []byte(testSimpleConfig), 0o600))
+
+	hash, err := cluster.HashSimpleConfig(configFile)
+	assert.NoError(t, err)
+
+	inlineHash, err := cluster.HashSimpleConfig(testSimpleConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, inlineHash, hash)
+
+	assert.NoError(t, os.WriteFile(configFile, []byte(testSimpleConfig+"registries:\n  create:\n    name: registry\n"), 0o600))
+
+	editedHash, err := cluster.HashSimpleConfig(configFile)
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, editedHash)
+}
+
+func TestValidateSimpleConfig(t *testing.T) {
+	t.Run("should not return errors for valid config", func(t *testing.T) {
+		assert.Empty(t, cluster.ValidateSimpleConfig(testSimpleConfig))
+	})
+
+	t.Run("should return an error for every invalid field", func(t *testing.T) {
+		errs := cluster.ValidateSimpleConfig("servers: three\nagents: two\n")
+		assert.Len(t, errs, 2)
+
+		for _, err := range errs {
+			assert.ErrorIs(t, err, terraformErrors.ErrInvalidSimpleConfig)
+		}
+	})
+
+	t.Run("should fail for config that is neither a file nor yaml document", func(t *testing.T) {
+		errs := cluster.ValidateSimpleConfig("/path/to/missing/config.yaml")
+		assert.Len(t, errs, 1)
+		assert.ErrorIs(t, errs[0], terraformErrors.ErrInvalidSimpleConfig)
+	})
+}
+
+func TestMergeSimpleConfig(t *testing.T) {
+	t.Run("attributes set should override the matching fields of config", func(t *testing.T) {
+		cfg, err := cluster.LoadSimpleConfig(testSimpleConfig)
+		assert.NoError(t, err)
+
+		overrides := &v1alpha4.SimpleConfig{
+			ObjectMeta: types.ObjectMeta{Name: "from-terraform"},
+			Agents:     1,
+		}
+
+		merged, err := cluster.MergeSimpleConfig(overrides, cfg)
+		assert.NoError(t, err)
+		assert.Equal(t, "from-terraform", merged.Name)
+		assert.Equal(t, 1, merged.Agents)
+		assert.Equal(t, 3, merged.Servers)
+		assert.Equal(t, "rancher/k3s:v1.24.4-k3s1", merged.Image)
+	})
+}
+
+func TestMarshalSimpleConfig(t *testing.T) {
+	simpleConfig := &v1alpha4.SimpleConfig{
+		ObjectMeta: types.ObjectMeta{Name: "from-cluster"},
+		Servers:    3,
+		Agents:     2,
+		Image:      "rancher/k3s:v1.24.4-k3s1",
+		Ports: []v1alpha4.PortWithNodeFilters{
+			{Port: "0.0.0.0:8080:80/TCP", NodeFilters: []string{"loadbalancer"}},
+		},
+	}
+
+	content, err := cluster.MarshalSimpleConfig(simpleConfig)
+	assert.NoError(t, err)
+	assert.Contains(t, content, "apiVersion: k3d.io/v1alpha4\n")
+	assert.Contains(t, content, "kind: Simple\n")
+	assert.Empty(t, cluster.ValidateSimpleConfig(content))
+
+	cfg, err := cluster.LoadSimpleConfig(content)
+	assert.NoError(t, err)
+	assert.Equal(t, "from-cluster", cfg.Name)
+	assert.Equal(t, 3, cfg.Servers)
+	assert.Equal(t, 2, cfg.Agents)
+	assert.Equal(t, simpleConfig.Ports, cfg.Ports)
+}
@@ -0,0 +1,152 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/docker/go-connections/nat"
+	terraformErrors "github.com/nikhilsbhat/terraform-provider-k3d/pkg/errors"
+	"github.com/rancher/k3d/v5/pkg/config"
+	"github.com/rancher/k3d/v5/pkg/config/v1alpha4"
+	"github.com/rancher/k3d/v5/pkg/runtimes"
+	runtimeutil "github.com/rancher/k3d/v5/pkg/runtimes/util"
+)
+
+// Fields of the SimpleConfig which are validated in isolation to find the one that makes the config invalid.
+const (
+	FieldVolumes     = "volumes"
+	FieldPorts       = "ports"
+	FieldEnv         = "env"
+	FieldK3sOptions  = "options.k3s"
+	FieldRuntime     = "options.runtime"
+	FieldHostAliases = "hostAliases"
+	FieldKubeAPI     = "kubeAPI"
+	FieldRegistries  = "registries"
+	FieldSubnet      = "subnet"
+)
+
+type configField struct {
+	name string
+	set  func(dst, src *v1alpha4.SimpleConfig)
+}
+
+var configFields = []configField{
+	{FieldVolumes, func(dst, src *v1alpha4.SimpleConfig) { dst.Volumes = src.Volumes }},
+	{FieldPorts, func(dst, src *v1alpha4.SimpleConfig) { dst.Ports = src.Ports }},
+	{FieldEnv, func(dst, src *v1alpha4.SimpleConfig) { dst.Env = src.Env }},
+	{FieldK3sOptions, func(dst, src *v1alpha4.SimpleConfig) { dst.Options.K3sOptions = src.Options.K3sOptions }},
+	{FieldRuntime, func(dst, src *v1alpha4.SimpleConfig) { dst.Options.Runtime = src.Options.Runtime }},
+	{FieldHostAliases, func(dst, src *v1alpha4.SimpleConfig) { dst.HostAliases = src.HostAliases }},
+	{FieldKubeAPI, func(dst, src *v1alpha4.SimpleConfig) { dst.ExposeAPI = src.ExposeAPI }},
+	{FieldRegistries, func(dst, src *v1alpha4.SimpleConfig) { dst.Registries = src.Registries }},
+	{FieldSubnet, func(dst, src *v1alpha4.SimpleConfig) { dst.Subnet = src.Subnet }},
+}
+
+// ValidateConfig runs the transformation, processing and validation steps of k3d that CreateCluster runs,
+// without creating anything in the runtime. When the config is invalid, every field is validated in isolation
+// so that InvalidConfigError could point to the field that made it invalid.
+func ValidateConfig(ctx context.Context, runtime runtimes.Runtime, cfg *v1alpha4.SimpleConfig) error {
+	err := validateConfig(ctx, runtime, cfg)
+	if err == nil {
+		return nil
+	}
+
+	base := *cfg
+	for _, field := range configFields {
+		field.set(&base, &v1alpha4.SimpleConfig{})
+	}
+
+	if baseErr := validateConfig(ctx, runtime, &base); baseErr != nil {
+		return fmt.Errorf("%w: %v", terraformErrors.ErrInvalidSimpleConfig, baseErr)
+	}
+
+	for _, field := range configFields {
+		isolated := base
+		field.set(&isolated, cfg)
+
+		if fieldErr := validateConfig(ctx, runtime, &isolated); fieldErr != nil {
+			return &terraformErrors.InvalidConfigError{Field: field.name, Err: fieldErr}
+		}
+	}
+
+	return fmt.Errorf("%w: %v", terraformErrors.ErrInvalidSimpleConfig, err)
+}
+
+func validateConfig(ctx context.Context, runtime runtimes.Runtime, cfg *v1alpha4.SimpleConfig) error {
+	simpleConfig := *cfg
+	// image is not validated by k3d, channels like 'latest' are resolved over network which is not required at plan.
+	simpleConfig.Image = ""
+
+	if err := validateHostPorts(&simpleConfig); err != nil {
+		return err
+	}
+
+	clusterConfig, err := config.TransformSimpleToClusterConfig(ctx, runtime, simpleConfig)
+	if err != nil {
+		return err
+	}
+
+	clusterConfig, err = config.ProcessClusterConfig(*clusterConfig)
+	if err != nil {
+		return err
+	}
+
+	// k3d creates the missing named volumes while validating them, hence only the mount spec of the volumes is validated here.
+	for _, node := range clusterConfig.Cluster.Nodes {
+		for _, volume := range node.Volumes {
+			if err = validateVolumeMount(volume); err != nil {
+				return err
+			}
+		}
+
+		node.Volumes = nil
+	}
+
+	return config.ValidateClusterConfig(ctx, runtime, *clusterConfig)
+}
+
+// validateHostPorts validates that a port of the host is not mapped more than once, including the port of the kubernetes API.
+func validateHostPorts(cfg *v1alpha4.SimpleConfig) error {
+	hostPorts := make(map[string]string)
+
+	if len(cfg.ExposeAPI.HostPort) != 0 {
+		hostPorts[fmt.Sprintf("%s/tcp", cfg.ExposeAPI.HostPort)] = FieldKubeAPI
+	}
+
+	for _, port := range cfg.Ports {
+		mappings, err := nat.ParsePortSpec(port.Port)
+		if err != nil {
+			return err
+		}
+
+		for _, mapping := range mappings {
+			if len(mapping.Binding.HostPort) == 0 || mapping.Binding.HostPort == "0" {
+				continue
+			}
+
+			hostPort := fmt.Sprintf("%s/%s", mapping.Binding.HostPort, mapping.Port.Proto())
+			if mappedBy, ok := hostPorts[hostPort]; ok {
+				return fmt.Errorf("%w: %s by %s and '%s'", terraformErrors.ErrDuplicateHostPort, hostPort, mappedBy, port.Port)
+			}
+
+			hostPorts[hostPort] = fmt.Sprintf("'%s'", port.Port)
+		}
+	}
+
+	return nil
+}
+
+// validateVolumeMount validates the mount spec of the volume the same way k3d does, except for the existence of its source.
+func validateVolumeMount(volume string) error {
+	_, destination, err := runtimeutil.ReadVolumeMount(volume)
+	if err != nil {
+		return err
+	}
+
+	if !strings.HasPrefix(destination, "/") {
+		return fmt.Errorf("volume mount destination should be an absolute path: '%s' in '%s'", destination, volume)
+	}
+
+	return nil
+}
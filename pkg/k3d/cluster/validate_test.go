@@ -0,0 +1,97 @@
+package cluster_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	terraformErrors "github.com/nikhilsbhat/terraform-provider-k3d/pkg/errors"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/k3d/cluster"
+	"github.com/rancher/k3d/v5/pkg/config/types"
+	"github.com/rancher/k3d/v5/pkg/config/v1alpha4"
+	"github.com/rancher/k3d/v5/pkg/runtimes"
+	"github.com/stretchr/testify/assert"
+)
+
+func getTestValidateConfig() *v1alpha4.SimpleConfig {
+	return &v1alpha4.SimpleConfig{
+		ObjectMeta: types.ObjectMeta{Name: "test"},
+		Servers:    1,
+		Agents:     2,
+		ExposeAPI:  v1alpha4.SimpleExposureOpts{HostIP: "0.0.0.0", HostPort: "6445"},
+		Ports: []v1alpha4.PortWithNodeFilters{
+			{Port: "0.0.0.0:8080:80/tcp", NodeFilters: []string{"loadbalancer"}},
+		},
+		Env: []v1alpha4.EnvVarWithNodeFilters{
+			{EnvVar: "LOG=debug", NodeFilters: []string{"agent:*"}},
+		},
+		Volumes: []v1alpha4.VolumeWithNodeFilters{
+			{Volume: "/tmp/data:/data", NodeFilters: []string{"server:0"}},
+		},
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should succeed for valid config", func(t *testing.T) {
+		assert.NoError(t, cluster.ValidateConfig(ctx, runtimes.SelectedRuntime, getTestValidateConfig()))
+	})
+
+	t.Run("should point to env for node filter matching no node", func(t *testing.T) {
+		cfg := getTestValidateConfig()
+		cfg.Env[0].NodeFilters = []string{"agent:5"}
+
+		var invalidConfigErr *terraformErrors.InvalidConfigError
+
+		err := cluster.ValidateConfig(ctx, runtimes.SelectedRuntime, cfg)
+		assert.ErrorAs(t, err, &invalidConfigErr)
+		assert.ErrorIs(t, err, terraformErrors.ErrInvalidSimpleConfig)
+		assert.Equal(t, cluster.FieldEnv, invalidConfigErr.Field)
+	})
+
+	t.Run("should point to ports for host port mapped twice", func(t *testing.T) {
+		cfg := getTestValidateConfig()
+		cfg.Ports = append(cfg.Ports, v1alpha4.PortWithNodeFilters{Port: "0.0.0.0:8080:8080/tcp", NodeFilters: []string{"agent:0"}})
+
+		var invalidConfigErr *terraformErrors.InvalidConfigError
+
+		err := cluster.ValidateConfig(ctx, runtimes.SelectedRuntime, cfg)
+		assert.ErrorAs(t, err, &invalidConfigErr)
+		assert.ErrorIs(t, err, terraformErrors.ErrDuplicateHostPort)
+		assert.Equal(t, cluster.FieldPorts, invalidConfigErr.Field)
+	})
+
+	t.Run("should point to runtime for invalid memory", func(t *testing.T) {
+		cfg := getTestValidateConfig()
+		cfg.Options.Runtime.AgentsMemory = "1Gibberish"
+
+		var invalidConfigErr *terraformErrors.InvalidConfigError
+
+		err := cluster.ValidateConfig(ctx, runtimes.SelectedRuntime, cfg)
+		assert.ErrorAs(t, err, &invalidConfigErr)
+		assert.Equal(t, cluster.FieldRuntime, invalidConfigErr.Field)
+	})
+
+	t.Run("should point to volumes for relative destination", func(t *testing.T) {
+		cfg := getTestValidateConfig()
+		cfg.Volumes[0].Volume = "/tmp/data:data"
+
+		var invalidConfigErr *terraformErrors.InvalidConfigError
+
+		err := cluster.ValidateConfig(ctx, runtimes.SelectedRuntime, cfg)
+		assert.ErrorAs(t, err, &invalidConfigErr)
+		assert.Equal(t, cluster.FieldVolumes, invalidConfigErr.Field)
+	})
+
+	t.Run("should fail without pointing to a field for invalid name", func(t *testing.T) {
+		cfg := getTestValidateConfig()
+		cfg.Name = "invalid_name"
+
+		var invalidConfigErr *terraformErrors.InvalidConfigError
+
+		err := cluster.ValidateConfig(ctx, runtimes.SelectedRuntime, cfg)
+		assert.ErrorIs(t, err, terraformErrors.ErrInvalidSimpleConfig)
+		assert.False(t, errors.As(err, &invalidConfigErr))
+	})
+}
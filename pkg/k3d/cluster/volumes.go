@@ -0,0 +1,132 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	terraformErrors "github.com/nikhilsbhat/terraform-provider-k3d/pkg/errors"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/utils"
+	"github.com/rancher/k3d/v5/pkg/config/v1alpha4"
+	"github.com/rancher/k3d/v5/pkg/runtimes"
+	runtimeErrors "github.com/rancher/k3d/v5/pkg/runtimes/errors"
+)
+
+const (
+	VolumeTypeBind  = "bind"
+	VolumeTypeNamed = "named"
+)
+
+// namedVolumeRegex is the pattern docker expects the names of the volumes to match.
+var namedVolumeRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]+$`)
+
+// Volume is either a path of the host or a named volume of the runtime, mounted into the nodes.
+type Volume struct {
+	Source      string
+	Destination string
+	Mode        string
+	Type        string
+	Retain      bool
+	NodeFilters []string
+}
+
+// GetVolumeWithNodeFilters returns the volume in the format k3d expects, [SOURCE:]DEST[:MODE].
+func (volume *Volume) GetVolumeWithNodeFilters() v1alpha4.VolumeWithNodeFilters {
+	spec := volume.Destination
+	if len(volume.Source) != 0 {
+		spec = fmt.Sprintf("%s:%s", volume.Source, spec)
+	}
+
+	if len(volume.Mode) != 0 {
+		spec = fmt.Sprintf("%s:%s", spec, volume.Mode)
+	}
+
+	return v1alpha4.VolumeWithNodeFilters{
+		Volume:      spec,
+		NodeFilters: volume.NodeFilters,
+	}
+}
+
+// Validate validates the source of the volume, the path should exist on the host for bind mounts and
+// should be a valid name for the named volumes.
+func (volume *Volume) Validate() error {
+	switch volume.Type {
+	case VolumeTypeNamed:
+		if !namedVolumeRegex.MatchString(volume.Source) {
+			return fmt.Errorf("%w: '%s' is not a valid name for named volume", terraformErrors.ErrInvalidVolume, volume.Source)
+		}
+	case VolumeTypeBind, "":
+		if len(volume.Source) == 0 {
+			return nil
+		}
+
+		if !filepath.IsAbs(volume.Source) {
+			return fmt.Errorf("%w: source '%s' should be an absolute path", terraformErrors.ErrInvalidVolume, volume.Source)
+		}
+
+		if _, err := os.Stat(volume.Source); err != nil {
+			return fmt.Errorf("%w: source '%s' errored with %v", terraformErrors.ErrInvalidVolume, volume.Source, err)
+		}
+	default:
+		return fmt.Errorf("%w: unsupported type '%s'", terraformErrors.ErrInvalidVolume, volume.Type)
+	}
+
+	if !strings.HasPrefix(volume.Destination, "/") {
+		return fmt.Errorf("%w: destination '%s' should be an absolute path", terraformErrors.ErrInvalidVolume, volume.Destination)
+	}
+
+	return nil
+}
+
+// CreateNamedVolumes creates the named volumes that do not exist yet, labelled as owned by the cluster.
+// Volumes that already exist are left untouched and are not owned by the cluster.
+func CreateNamedVolumes(ctx context.Context, runtime runtimes.Runtime, clusterName string, volumes []*Volume) error {
+	for _, volume := range volumes {
+		if volume.Type != VolumeTypeNamed {
+			continue
+		}
+
+		_, err := runtime.GetVolume(volume.Source)
+		if err == nil {
+			continue
+		}
+
+		if !terraformErrors.IsNotFound(terraformErrors.ClassifyLookupError("volume", volume.Source, err)) {
+			return fmt.Errorf("fetching volume '%s' errored with: %w", volume.Source, err)
+		}
+
+		if err = runtime.CreateVolume(ctx, volume.Source, getVolumeLabels(clusterName)); err != nil {
+			return fmt.Errorf("creating volume '%s' errored with: %w", volume.Source, err)
+		}
+	}
+
+	return nil
+}
+
+// DeleteNamedVolumes deletes the named volumes owned by the cluster, except the ones to be retained.
+func DeleteNamedVolumes(ctx context.Context, runtime runtimes.Runtime, clusterName string, volumes []*Volume) error {
+	ownedVolumes, err := runtime.GetVolumesByLabel(ctx, getVolumeLabels(clusterName))
+	if err != nil {
+		return fmt.Errorf("fetching volumes of cluster '%s' errored with: %w", clusterName, err)
+	}
+
+	for _, volume := range volumes {
+		if volume.Type != VolumeTypeNamed || volume.Retain || !utils.Contains(ownedVolumes, volume.Source) {
+			continue
+		}
+
+		if err = runtime.DeleteVolume(ctx, volume.Source); err != nil && !errors.Is(err, runtimeErrors.ErrRuntimeVolumeNotExists) {
+			return fmt.Errorf("deleting volume '%s' errored with: %w", volume.Source, err)
+		}
+	}
+
+	return nil
+}
+
+func getVolumeLabels(clusterName string) map[string]string {
+	return map[string]string{utils.TerraformVolumeClusterLabel: clusterName}
+}
@@ -0,0 +1,48 @@
+package cluster_test
+
+import (
+	"testing"
+
+	terraformErrors "github.com/nikhilsbhat/terraform-provider-k3d/pkg/errors"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/k3d/cluster"
+	"github.com/rancher/k3d/v5/pkg/config/v1alpha4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVolume_GetVolumeWithNodeFilters(t *testing.T) {
+	t.Run("should build the volume with source and mode", func(t *testing.T) {
+		volume := &cluster.Volume{Source: "/tmp/data", Destination: "/data", Mode: "ro", NodeFilters: []string{"server:*"}}
+
+		expected := v1alpha4.VolumeWithNodeFilters{Volume: "/tmp/data:/data:ro", NodeFilters: []string{"server:*"}}
+		assert.Equal(t, expected, volume.GetVolumeWithNodeFilters())
+	})
+
+	t.Run("should build the volume with only the destination", func(t *testing.T) {
+		volume := &cluster.Volume{Destination: "/data"}
+
+		assert.Equal(t, "/data", volume.GetVolumeWithNodeFilters().Volume)
+	})
+}
+
+func TestVolume_Validate(t *testing.T) {
+	t.Run("should validate the named volume", func(t *testing.T) {
+		assert.NoError(t, (&cluster.Volume{Source: "k3d-cache", Destination: "/cache", Type: cluster.VolumeTypeNamed}).Validate())
+	})
+
+	t.Run("should validate the existing bind mount", func(t *testing.T) {
+		assert.NoError(t, (&cluster.Volume{Source: t.TempDir(), Destination: "/data", Type: cluster.VolumeTypeBind}).Validate())
+	})
+
+	for name, volume := range map[string]*cluster.Volume{
+		"should fail for invalid name of named volume": {Source: "/cache", Destination: "/cache", Type: cluster.VolumeTypeNamed},
+		"should fail for missing path of bind mount":   {Source: "/non/existent/path", Destination: "/data", Type: cluster.VolumeTypeBind},
+		"should fail for relative path of bind mount":  {Source: "data", Destination: "/data", Type: cluster.VolumeTypeBind},
+		"should fail for relative destination":         {Source: "k3d-cache", Destination: "cache", Type: cluster.VolumeTypeNamed},
+		"should fail for unsupported type":             {Source: "/tmp", Destination: "/data", Type: "tmpfs"},
+	} {
+		volume := volume
+		t.Run(name, func(t *testing.T) {
+			assert.ErrorIs(t, volume.Validate(), terraformErrors.ErrInvalidVolume)
+		})
+	}
+}
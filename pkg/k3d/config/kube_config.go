@@ -2,12 +2,15 @@ package config
 
 import (
 	"context"
+	"fmt"
 
+	terraformErrors "github.com/nikhilsbhat/terraform-provider-k3d/pkg/errors"
 	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/utils"
 	"github.com/rancher/k3d/v5/pkg/client"
 	"github.com/rancher/k3d/v5/pkg/runtimes"
 	K3D "github.com/rancher/k3d/v5/pkg/types"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
 )
 
 // GetKubeConfig fetches kubernetes config from the specified clusters.
@@ -65,3 +68,46 @@ func (cfg *Config) GetKubeConfig(ctx context.Context, runtime runtimes.Runtime)
 
 	return kubeConfigs, nil
 }
+
+// Credentials holds the endpoint of the kube API and the client credentials to access it.
+type Credentials struct {
+	Host                 string
+	ClusterCACertificate string
+	ClientCertificate    string
+	ClientKey            string
+}
+
+// GetCredentials fetches the kube API endpoint and client credentials of the specified cluster from its kubeconfig.
+func GetCredentials(ctx context.Context, runtime runtimes.Runtime, cluster *K3D.Cluster) (*Credentials, error) {
+	kubeConfig, err := client.KubeconfigGet(ctx, runtime, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetCredentialsFromKubeConfig(kubeConfig)
+}
+
+// GetCredentialsFromKubeConfig reads the kube API endpoint and client credentials from the current context of the kubeconfig.
+func GetCredentialsFromKubeConfig(kubeConfig *api.Config) (*Credentials, error) {
+	kubeContext, ok := kubeConfig.Contexts[kubeConfig.CurrentContext]
+	if !ok {
+		return nil, fmt.Errorf("%w: context '%s'", terraformErrors.ErrInvalidKubeConfig, kubeConfig.CurrentContext)
+	}
+
+	kubeCluster, ok := kubeConfig.Clusters[kubeContext.Cluster]
+	if !ok {
+		return nil, fmt.Errorf("%w: cluster '%s'", terraformErrors.ErrInvalidKubeConfig, kubeContext.Cluster)
+	}
+
+	authInfo, ok := kubeConfig.AuthInfos[kubeContext.AuthInfo]
+	if !ok {
+		return nil, fmt.Errorf("%w: user '%s'", terraformErrors.ErrInvalidKubeConfig, kubeContext.AuthInfo)
+	}
+
+	return &Credentials{
+		Host:                 kubeCluster.Server,
+		ClusterCACertificate: string(kubeCluster.CertificateAuthorityData),
+		ClientCertificate:    string(authInfo.ClientCertificateData),
+		ClientKey:            string(authInfo.ClientKeyData),
+	}, nil
+}
@@ -0,0 +1,281 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	terraformErrors "github.com/nikhilsbhat/terraform-provider-k3d/pkg/errors"
+	"github.com/rancher/k3d/v5/pkg/client"
+	"github.com/rancher/k3d/v5/pkg/runtimes"
+	K3D "github.com/rancher/k3d/v5/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+const (
+	kubeConfigFileMode  = 0o600
+	kubeConfigDirMode   = 0o700
+	sampleClusterName   = "sample"
+	contextNameTemplate = "context_name"
+)
+
+// KubeConfigOptions holds the options to customise the kubeconfig of a cluster and the places it has to be written to.
+type KubeConfigOptions struct {
+	UpdateDefault  bool
+	SwitchContext  bool
+	OutputPath     string
+	ContextName    string
+	ServerOverride string
+}
+
+// contextNameData is the data available to the context name template.
+type contextNameData struct {
+	Name string
+}
+
+// GetContextName renders the context name template for the cluster, k3d's default k3d-<cluster> is used when the template is empty.
+func GetContextName(contextName, clusterName string) (string, error) {
+	if len(contextName) == 0 {
+		return fmt.Sprintf("%s-%s", K3D.DefaultObjectNamePrefix, clusterName), nil
+	}
+
+	tmpl, err := template.New(contextNameTemplate).Parse(contextName)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", terraformErrors.ErrInvalidContextName, err)
+	}
+
+	var rendered bytes.Buffer
+	if err = tmpl.Execute(&rendered, contextNameData{Name: clusterName}); err != nil {
+		return "", fmt.Errorf("%w: %v", terraformErrors.ErrInvalidContextName, err)
+	}
+
+	return rendered.String(), nil
+}
+
+// ValidateContextName validates the context name template by rendering it for a sample cluster.
+func ValidateContextName(contextName string) error {
+	rendered, err := GetContextName(contextName, sampleClusterName)
+	if err != nil {
+		return err
+	}
+
+	if len(rendered) == 0 {
+		return fmt.Errorf("%w: template '%s' renders to empty string", terraformErrors.ErrInvalidContextName, contextName)
+	}
+
+	return nil
+}
+
+// CustomizeKubeConfig renames the current context of the kubeconfig and overrides the server of the cluster it refers to.
+func CustomizeKubeConfig(kubeConfig *api.Config, contextName, serverOverride string) error {
+	kubeContext, ok := kubeConfig.Contexts[kubeConfig.CurrentContext]
+	if !ok {
+		return fmt.Errorf("%w: context '%s'", terraformErrors.ErrInvalidKubeConfig, kubeConfig.CurrentContext)
+	}
+
+	if len(serverOverride) != 0 {
+		kubeCluster, ok := kubeConfig.Clusters[kubeContext.Cluster]
+		if !ok {
+			return fmt.Errorf("%w: cluster '%s'", terraformErrors.ErrInvalidKubeConfig, kubeContext.Cluster)
+		}
+
+		kubeCluster.Server = serverOverride
+	}
+
+	if len(contextName) != 0 && contextName != kubeConfig.CurrentContext {
+		delete(kubeConfig.Contexts, kubeConfig.CurrentContext)
+		kubeConfig.Contexts[contextName] = kubeContext
+		kubeConfig.CurrentContext = contextName
+	}
+
+	return nil
+}
+
+// GetKubeConfig fetches the kubeconfig of the cluster customised as per the options.
+func (opts *KubeConfigOptions) GetKubeConfig(ctx context.Context, runtime runtimes.Runtime, cluster *K3D.Cluster) (*api.Config, error) {
+	kubeConfig, err := client.KubeconfigGet(ctx, runtime, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	contextName, err := GetContextName(opts.ContextName, cluster.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = CustomizeKubeConfig(kubeConfig, contextName, opts.ServerOverride); err != nil {
+		return nil, err
+	}
+
+	return kubeConfig, nil
+}
+
+// Write writes the kubeconfig of the cluster to the output path and merges it to the default kubeconfig, if enabled.
+func (opts *KubeConfigOptions) Write(ctx context.Context, runtime runtimes.Runtime, cluster *K3D.Cluster) error {
+	if len(opts.OutputPath) == 0 && !opts.UpdateDefault {
+		return nil
+	}
+
+	kubeConfig, err := opts.GetKubeConfig(ctx, runtime, cluster)
+	if err != nil {
+		return err
+	}
+
+	if len(opts.OutputPath) != 0 {
+		if err = WriteKubeConfigFile(kubeConfig, opts.OutputPath); err != nil {
+			return err
+		}
+	}
+
+	if !opts.UpdateDefault {
+		return nil
+	}
+
+	defaultKubeConfigPath, err := client.KubeconfigGetDefaultPath()
+	if err != nil {
+		return err
+	}
+
+	existingKubeConfig, err := loadKubeConfigFile(defaultKubeConfigPath)
+	if err != nil {
+		return err
+	}
+
+	return client.KubeconfigMerge(ctx, kubeConfig, existingKubeConfig, defaultKubeConfigPath, true, opts.SwitchContext)
+}
+
+// InSync checks if the kubeconfig written to the output path still matches the kubeconfig of the cluster,
+// the file is considered out of sync when it is missing, modified or has permissions other than 0600.
+func (opts *KubeConfigOptions) InSync(ctx context.Context, runtime runtimes.Runtime, cluster *K3D.Cluster) (bool, error) {
+	if len(opts.OutputPath) == 0 {
+		return true, nil
+	}
+
+	info, err := os.Stat(opts.OutputPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	if info.Mode().Perm() != kubeConfigFileMode {
+		return false, nil
+	}
+
+	kubeConfig, err := opts.GetKubeConfig(ctx, runtime, cluster)
+	if err != nil {
+		return false, err
+	}
+
+	expected, err := clientcmd.Write(*kubeConfig)
+	if err != nil {
+		return false, err
+	}
+
+	actual, err := os.ReadFile(opts.OutputPath)
+	if err != nil {
+		return false, err
+	}
+
+	return bytes.Equal(expected, actual), nil
+}
+
+// Remove deletes the kubeconfig written to the output path and removes the cluster from the default kubeconfig, if it was updated.
+func (opts *KubeConfigOptions) Remove(ctx context.Context, cluster *K3D.Cluster) error {
+	if len(opts.OutputPath) != 0 {
+		if err := os.Remove(opts.OutputPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+
+	if !opts.UpdateDefault {
+		return nil
+	}
+
+	defaultKubeConfigPath, err := client.KubeconfigGetDefaultPath()
+	if err != nil {
+		return err
+	}
+
+	if _, err = os.Stat(defaultKubeConfigPath); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	if err = client.KubeconfigRemoveClusterFromDefaultConfig(ctx, cluster); err != nil {
+		return err
+	}
+
+	contextName, err := GetContextName(opts.ContextName, cluster.Name)
+	if err != nil {
+		return err
+	}
+
+	kubeConfig, err := loadKubeConfigFile(defaultKubeConfigPath)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := kubeConfig.Contexts[contextName]; !ok {
+		return nil
+	}
+
+	RemoveContext(kubeConfig, contextName)
+
+	return client.KubeconfigWrite(ctx, kubeConfig, defaultKubeConfigPath)
+}
+
+// RemoveContext removes the context from the kubeconfig, current-context is switched to any other context if it referred the removed one.
+func RemoveContext(kubeConfig *api.Config, contextName string) {
+	delete(kubeConfig.Contexts, contextName)
+
+	if kubeConfig.CurrentContext != contextName {
+		return
+	}
+
+	kubeConfig.CurrentContext = ""
+	for name := range kubeConfig.Contexts {
+		kubeConfig.CurrentContext = name
+
+		break
+	}
+}
+
+// WriteKubeConfigFile writes the kubeconfig to the path with 0600 permissions, creating the parent directories if required.
+func WriteKubeConfigFile(kubeConfig *api.Config, path string) error {
+	content, err := clientcmd.Write(*kubeConfig)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), kubeConfigDirMode); err != nil {
+		return err
+	}
+
+	if err = os.WriteFile(path, content, kubeConfigFileMode); err != nil {
+		return err
+	}
+
+	// os.WriteFile does not change the permissions of the file if it already exists.
+	return os.Chmod(path, kubeConfigFileMode)
+}
+
+func loadKubeConfigFile(path string) (*api.Config, error) {
+	kubeConfig, err := clientcmd.LoadFromFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return api.NewConfig(), nil
+		}
+
+		return nil, err
+	}
+
+	return kubeConfig, nil
+}
@@ -0,0 +1,87 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	terraformErrors "github.com/nikhilsbhat/terraform-provider-k3d/pkg/errors"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/k3d/config"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func getTestKubeConfig() *api.Config {
+	return &api.Config{
+		Clusters: map[string]*api.Cluster{
+			"k3d-test": {Server: "https://0.0.0.0:6445"},
+		},
+		AuthInfos: map[string]*api.AuthInfo{
+			"admin@k3d-test": {ClientKeyData: []byte("key-data")},
+		},
+		Contexts: map[string]*api.Context{
+			"k3d-test": {Cluster: "k3d-test", AuthInfo: "admin@k3d-test"},
+		},
+		CurrentContext: "k3d-test",
+	}
+}
+
+func TestGetContextName(t *testing.T) {
+	t.Run("should default to the context name set by k3d", func(t *testing.T) {
+		contextName, err := config.GetContextName("", "test")
+		assert.NoError(t, err)
+		assert.Equal(t, "k3d-test", contextName)
+	})
+
+	t.Run("should render the template with cluster name", func(t *testing.T) {
+		contextName, err := config.GetContextName("dev-{{ .Name }}", "test")
+		assert.NoError(t, err)
+		assert.Equal(t, "dev-test", contextName)
+	})
+
+	t.Run("should fail for invalid template", func(t *testing.T) {
+		_, err := config.GetContextName("dev-{{ .Cluster }}", "test")
+		assert.ErrorIs(t, err, terraformErrors.ErrInvalidContextName)
+	})
+}
+
+func TestValidateContextName(t *testing.T) {
+	assert.NoError(t, config.ValidateContextName("dev-{{ .Name }}"))
+	assert.ErrorIs(t, config.ValidateContextName("{{ if false }}{{ end }}"), terraformErrors.ErrInvalidContextName)
+}
+
+func TestCustomizeKubeConfig(t *testing.T) {
+	kubeConfig := getTestKubeConfig()
+
+	assert.NoError(t, config.CustomizeKubeConfig(kubeConfig, "dev-test", "https://k3d.local:6443"))
+	assert.Equal(t, "dev-test", kubeConfig.CurrentContext)
+	assert.Contains(t, kubeConfig.Contexts, "dev-test")
+	assert.NotContains(t, kubeConfig.Contexts, "k3d-test")
+	assert.Equal(t, "https://k3d.local:6443", kubeConfig.Clusters["k3d-test"].Server)
+}
+
+func TestRemoveContext(t *testing.T) {
+	kubeConfig := getTestKubeConfig()
+	kubeConfig.Contexts["other"] = &api.Context{Cluster: "other", AuthInfo: "other"}
+
+	config.RemoveContext(kubeConfig, "k3d-test")
+	assert.NotContains(t, kubeConfig.Contexts, "k3d-test")
+	assert.Equal(t, "other", kubeConfig.CurrentContext)
+}
+
+func TestWriteKubeConfigFile(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "kube", "config")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(outputPath), 0o755))
+	assert.NoError(t, os.WriteFile(outputPath, []byte("stale"), 0o644))
+
+	assert.NoError(t, config.WriteKubeConfigFile(getTestKubeConfig(), outputPath))
+
+	info, err := os.Stat(outputPath)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	kubeConfig, err := clientcmd.LoadFromFile(outputPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "k3d-test", kubeConfig.CurrentContext)
+}
@@ -2,6 +2,11 @@ package config_test
 
 import (
 	"testing"
+
+	terraformErrors "github.com/nikhilsbhat/terraform-provider-k3d/pkg/errors"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/k3d/config"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/tools/clientcmd/api"
 )
 
 func TestGetKubeConfig(t *testing.T) {
@@ -59,3 +64,38 @@ func TestGetKubeConfig(t *testing.T) {
 	//		assert.Equal(t, expected, actual)
 	//	})
 }
+
+func TestGetCredentialsFromKubeConfig(t *testing.T) {
+	kubeConfig := &api.Config{
+		Clusters: map[string]*api.Cluster{
+			"k3d-test": {Server: "https://127.0.0.1:6445", CertificateAuthorityData: []byte("ca-data")},
+		},
+		AuthInfos: map[string]*api.AuthInfo{
+			"admin@k3d-test": {ClientCertificateData: []byte("cert-data"), ClientKeyData: []byte("key-data")},
+		},
+		Contexts: map[string]*api.Context{
+			"k3d-test": {Cluster: "k3d-test", AuthInfo: "admin@k3d-test"},
+		},
+		CurrentContext: "k3d-test",
+	}
+
+	t.Run("should read the credentials from current context", func(t *testing.T) {
+		expected := &config.Credentials{
+			Host:                 "https://127.0.0.1:6445",
+			ClusterCACertificate: "ca-data",
+			ClientCertificate:    "cert-data",
+			ClientKey:            "key-data",
+		}
+
+		actual, err := config.GetCredentialsFromKubeConfig(kubeConfig)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("should fail when current context is missing", func(t *testing.T) {
+		kubeConfig.CurrentContext = "k3d-missing"
+
+		_, err := config.GetCredentialsFromKubeConfig(kubeConfig)
+		assert.ErrorIs(t, err, terraformErrors.ErrInvalidKubeConfig)
+	})
+}
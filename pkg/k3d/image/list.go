@@ -3,6 +3,7 @@ package image
 import (
 	"context"
 
+	terraformErrors "github.com/nikhilsbhat/terraform-provider-k3d/pkg/errors"
 	cluster2 "github.com/nikhilsbhat/terraform-provider-k3d/pkg/k3d/cluster"
 	"github.com/rancher/k3d/v5/pkg/runtimes"
 )
@@ -18,6 +19,10 @@ func (image *Config) List(ctx context.Context, runtime runtimes.Runtime) ([]*Sto
 		return nil, err
 	}
 
+	if !image.All && len(retrievedClusters) == 0 {
+		return nil, terraformErrors.NewNotFoundError("cluster", image.Cluster)
+	}
+
 	storedImages := make([]*StoredImages, 0)
 	for _, retrievedCluster := range retrievedClusters {
 		storedImages = append(storedImages, &StoredImages{
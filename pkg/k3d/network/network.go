@@ -0,0 +1,123 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	dockerNetwork "github.com/docker/docker/api/types/network"
+	dockerClient "github.com/docker/docker/client"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/client"
+	terraformErrors "github.com/nikhilsbhat/terraform-provider-k3d/pkg/errors"
+	"github.com/rancher/k3d/v5/pkg/runtimes"
+)
+
+const networkDriver = "bridge"
+
+// Create creates the network with the IPAM settings and labels of the config, the ID of the created network is set on the config.
+// It errors if the network already exists, since the network created elsewhere should not be managed by the config.
+// The network is created with the client of the runtime, since k3d creates the networks without the labels or IPAM settings.
+func (cfg *Config) Create(ctx context.Context, runtime runtimes.Runtime) error {
+	runtimeClient, err := client.GetDockerClient(runtime)
+	if err != nil {
+		return err
+	}
+
+	defer runtimeClient.Close()
+
+	createdNetwork, err := runtimeClient.NetworkCreate(ctx, cfg.Name, types.NetworkCreate{
+		CheckDuplicate: true,
+		Driver:         networkDriver,
+		IPAM:           cfg.getIPAM(),
+		Labels:         cfg.Labels,
+	})
+	if err != nil {
+		return fmt.Errorf("creating network '%s' errored with: %w", cfg.Name, err)
+	}
+
+	cfg.ID = createdNetwork.ID
+
+	return nil
+}
+
+// Get fetches the network by its ID, or by name when the ID is not known yet.
+// The network is inspected with the client of the runtime, since k3d reports neither the labels nor the IPAM settings of it.
+func (cfg *Config) Get(ctx context.Context, runtime runtimes.Runtime) (*Config, error) {
+	runtimeClient, err := client.GetDockerClient(runtime)
+	if err != nil {
+		return nil, err
+	}
+
+	defer runtimeClient.Close()
+
+	networkID := cfg.ID
+	if len(networkID) == 0 {
+		networkID = cfg.Name
+	}
+
+	networkDetails, err := runtimeClient.NetworkInspect(ctx, networkID, types.NetworkInspectOptions{})
+	if err != nil {
+		if dockerClient.IsErrNotFound(err) {
+			return nil, &terraformErrors.NotFoundError{Kind: "network", Name: networkID, Err: err}
+		}
+
+		return nil, fmt.Errorf("fetching network '%s' errored with: %w", networkID, err)
+	}
+
+	return getNetworkConfig(networkDetails), nil
+}
+
+// Delete deletes the network, only when no k3d nodes are attached to it anymore.
+func (cfg *Config) Delete(ctx context.Context, runtime runtimes.Runtime) error {
+	nodes, err := runtime.GetNodesInNetwork(ctx, cfg.Name)
+	if err != nil {
+		return fmt.Errorf("fetching nodes attached to network '%s' errored with: %w", cfg.Name, err)
+	}
+
+	if len(nodes) != 0 {
+		nodeNames := make([]string, 0)
+		for _, node := range nodes {
+			nodeNames = append(nodeNames, node.Name)
+		}
+
+		return fmt.Errorf("%w: '%s' is used by %s", terraformErrors.ErrNetworkInUse, cfg.Name, strings.Join(nodeNames, ", "))
+	}
+
+	return runtime.DeleteNetwork(ctx, cfg.ID)
+}
+
+// getIPAM returns the IPAM settings of the network, nil lets the runtime pick the subnet.
+func (cfg *Config) getIPAM() *dockerNetwork.IPAM {
+	if len(cfg.Subnet) == 0 {
+		return nil
+	}
+
+	return &dockerNetwork.IPAM{
+		Config: []dockerNetwork.IPAMConfig{
+			{
+				Subnet:  cfg.Subnet,
+				Gateway: cfg.Gateway,
+				IPRange: cfg.IPRange,
+			},
+		},
+	}
+}
+
+// getNetworkConfig translates the network inspected from the runtime to Config,
+// where the IPAM settings are read from the first IPAM config, the one the network is created with.
+func getNetworkConfig(networkDetails types.NetworkResource) *Config {
+	cfg := &Config{
+		Name:   networkDetails.Name,
+		ID:     networkDetails.ID,
+		Labels: networkDetails.Labels,
+	}
+
+	if len(networkDetails.IPAM.Config) != 0 {
+		cfg.Subnet = networkDetails.IPAM.Config[0].Subnet
+		cfg.Gateway = networkDetails.IPAM.Config[0].Gateway
+		cfg.IPRange = networkDetails.IPAM.Config[0].IPRange
+	}
+
+	return cfg
+}
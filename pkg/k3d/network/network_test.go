@@ -0,0 +1,49 @@
+//nolint:testpackage
+package network
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	dockerNetwork "github.com/docker/docker/api/types/network"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_getIPAM(t *testing.T) {
+	t.Run("should leave the subnet to the runtime when not set", func(t *testing.T) {
+		cfg := &Config{Name: "k3d-shared"}
+
+		assert.Nil(t, cfg.getIPAM())
+	})
+
+	t.Run("should set the subnet, gateway and ip range when set", func(t *testing.T) {
+		cfg := &Config{Name: "k3d-shared", Subnet: "172.28.0.0/16", Gateway: "172.28.0.1", IPRange: "172.28.5.0/24"}
+
+		expected := &dockerNetwork.IPAM{
+			Config: []dockerNetwork.IPAMConfig{{Subnet: "172.28.0.0/16", Gateway: "172.28.0.1", IPRange: "172.28.5.0/24"}},
+		}
+
+		assert.Equal(t, expected, cfg.getIPAM())
+	})
+}
+
+func Test_getNetworkConfig(t *testing.T) {
+	networkDetails := types.NetworkResource{
+		Name:   "k3d-shared",
+		ID:     "4f1c0b4e0b1d",
+		Labels: map[string]string{"team": "platform"},
+		IPAM: dockerNetwork.IPAM{
+			Config: []dockerNetwork.IPAMConfig{{Subnet: "172.28.0.0/16", Gateway: "172.28.0.1"}},
+		},
+	}
+
+	expected := &Config{
+		Name:    "k3d-shared",
+		ID:      "4f1c0b4e0b1d",
+		Subnet:  "172.28.0.0/16",
+		Gateway: "172.28.0.1",
+		Labels:  map[string]string{"team": "platform"},
+	}
+
+	assert.Equal(t, expected, getNetworkConfig(networkDetails))
+}
@@ -0,0 +1,23 @@
+package network
+
+import (
+	"context"
+
+	"github.com/rancher/k3d/v5/pkg/runtimes"
+)
+
+type Network interface {
+	Create(ctx context.Context, runtime runtimes.Runtime) error
+	Get(ctx context.Context, runtime runtimes.Runtime) (*Config, error)
+	Delete(ctx context.Context, runtime runtimes.Runtime) error
+}
+
+// Config holds the settings of the runtime network shared by the clusters and registries.
+type Config struct {
+	Name    string            `json:"name,omitempty"     mapstructure:"name"`
+	ID      string            `json:"id,omitempty"       mapstructure:"id"`
+	Subnet  string            `json:"subnet,omitempty"   mapstructure:"subnet"`
+	Gateway string            `json:"gateway,omitempty"  mapstructure:"gateway"`
+	IPRange string            `json:"ip_range,omitempty" mapstructure:"ip_range"`
+	Labels  map[string]string `json:"labels,omitempty"   mapstructure:"labels"`
+}
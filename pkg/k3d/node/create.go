@@ -8,6 +8,7 @@ import (
 	dockerunits "github.com/docker/go-units"
 	terraformErrors "github.com/nikhilsbhat/terraform-provider-k3d/pkg/errors"
 	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/k3d/cluster"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/utils"
 	"github.com/rancher/k3d/v5/pkg/client"
 	"github.com/rancher/k3d/v5/pkg/runtimes"
 	K3D "github.com/rancher/k3d/v5/pkg/types"
@@ -41,6 +42,11 @@ func (cfg *Config) CreateNodeWithTimeout(ctx context.Context, runtime runtimes.R
 		k3dNodes = append(k3dNodes, node.GetNodeFromConfig())
 	}
 
+	// nodes with settings of their own are joined with only those, rather than the ones copied from the other nodes.
+	if cfg.HasOwnSettings() {
+		return JoinNodes(ctx, runtime, clusterFetched, k3dNodes, nodeCreatOpts)
+	}
+
 	if err = client.NodeAddToClusterMulti(ctx, runtime, k3dNodes, clusterFetched, nodeCreatOpts); err != nil {
 		return err
 	}
@@ -48,38 +54,21 @@ func (cfg *Config) CreateNodeWithTimeout(ctx context.Context, runtime runtimes.R
 	return nil
 }
 
-// CreateNodes creates number nodes specified in 'replicas', making this startFrom if in case we support update nodes on it.
+// CreateNodes creates the nodes indexed from startFrom up to the count of the config, so that the nodes could be added to the existing ones when scaled.
 func (cfg *Config) CreateNodes(ctx context.Context, runtime runtimes.Runtime, startFrom int) error {
-	nodesToCreate := make([]*Config, 0)
-
 	if _, err := dockerunits.RAMInBytes(cfg.Memory); cfg.Memory != "" && err != nil {
 		return terraformErrors.ErrInvalidMemoryLimit
 	}
 
-	for startFrom < cfg.Count {
-		nodesToCreate = append(nodesToCreate, &Config{
-			Name:    []string{fmt.Sprintf("%s-%d", cfg.Name[0], startFrom)},
-			Role:    cfg.Role,
-			Image:   cfg.Image,
-			Memory:  cfg.Memory,
-			Created: cfg.Created,
-		})
-
-		startFrom++
-	}
+	nodesToCreate := cfg.getNodesToCreate(startFrom)
 
 	if createRrr := cfg.CreateNodeWithTimeout(ctx, runtime, nodesToCreate); createRrr != nil {
 		log.Printf("creating nodes errord with: %v, cleaning up the created nodes to avoid dangling nodes", createRrr)
 
-		for _, nodeToCreate := range nodesToCreate {
-			nd := nodeToCreate.GetNodeFromConfig()
+		rollbackCtx, cancel := utils.RollbackContext(ctx)
+		defer cancel()
 
-			log.Printf("cleaning up node: %s", nd.Name)
-
-			if err := nodeToCreate.DeleteNodesFromCluster(ctx, runtime); err != nil {
-				log.Printf("errored while deleting node %s : %v", nodeToCreate.Name[0], err)
-			}
-		}
+		rollbackNodes(rollbackCtx, runtime, nodesToCreate)
 
 		log.Printf("creating nodes failed")
 
@@ -88,3 +77,42 @@ func (cfg *Config) CreateNodes(ctx context.Context, runtime runtimes.Runtime, st
 
 	return nil
 }
+
+// getNodesToCreate returns the configs of the nodes indexed from startFrom up to the count of the config.
+// The nodes are associated with the cluster, so that they could be found and removed when the creation fails.
+func (cfg *Config) getNodesToCreate(startFrom int) []*Config {
+	nodesToCreate := make([]*Config, 0)
+
+	for startFrom < cfg.Count {
+		nodesToCreate = append(nodesToCreate, &Config{
+			Name:                 []string{fmt.Sprintf("%s-%d", cfg.Name[0], startFrom)},
+			ClusterAssociated:    cfg.ClusterAssociated,
+			Role:                 cfg.Role,
+			Image:                cfg.Image,
+			Memory:               cfg.Memory,
+			Created:              cfg.Created,
+			Volumes:              cfg.Volumes,
+			EnvironmentVariables: cfg.EnvironmentVariables,
+			Pool:                 cfg.Pool,
+			K3sNodeLabels:        cfg.K3sNodeLabels,
+			K3sNodeTaints:        cfg.K3sNodeTaints,
+			K3sArgs:              cfg.K3sArgs,
+		})
+
+		startFrom++
+	}
+
+	return nodesToCreate
+}
+
+// rollbackNodes removes the nodes that were created before the creation of the rest of them failed,
+// the nodes that were never created are not found in the cluster and are skipped.
+func rollbackNodes(ctx context.Context, runtime runtimes.Runtime, nodes []*Config) {
+	for _, node := range nodes {
+		log.Printf("cleaning up node: %s", node.Name[0])
+
+		if err := node.DeleteNodesFromCluster(ctx, runtime); err != nil {
+			log.Printf("errored while deleting node %s : %v", node.Name[0], err)
+		}
+	}
+}
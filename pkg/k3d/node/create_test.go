@@ -0,0 +1,122 @@
+//nolint:testpackage
+package node
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rancher/k3d/v5/pkg/runtimes"
+	K3D "github.com/rancher/k3d/v5/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubRuntime holds the nodes of the runtime, looked up by their labels and removed when deleted, the rest of the runtime is not implemented.
+type stubRuntime struct {
+	runtimes.Runtime
+	nodes   []*K3D.Node
+	deleted []string
+}
+
+func (runtime *stubRuntime) GetNodesByLabel(_ context.Context, labels map[string]string) ([]*K3D.Node, error) {
+	nodes := make([]*K3D.Node, 0)
+
+	for _, node := range runtime.nodes {
+		matches := true
+
+		for key, value := range labels {
+			if node.RuntimeLabels[key] != value {
+				matches = false
+			}
+		}
+
+		if matches {
+			nodes = append(nodes, node)
+		}
+	}
+
+	return nodes, nil
+}
+
+func (runtime *stubRuntime) DeleteNode(_ context.Context, node *K3D.Node) error {
+	runtime.deleted = append(runtime.deleted, node.Name)
+
+	nodes := make([]*K3D.Node, 0, len(runtime.nodes))
+
+	for _, existing := range runtime.nodes {
+		if existing.Name != node.Name {
+			nodes = append(nodes, existing)
+		}
+	}
+
+	runtime.nodes = nodes
+
+	return nil
+}
+
+func (runtime *stubRuntime) GetVolumesByLabel(_ context.Context, _ map[string]string) ([]string, error) {
+	return nil, nil
+}
+
+func getTestClusterNode(name string, role K3D.Role) *K3D.Node {
+	return &K3D.Node{
+		Name:          name,
+		Role:          role,
+		RuntimeLabels: map[string]string{K3D.LabelClusterName: "test", K3D.LabelRole: string(role)},
+	}
+}
+
+func TestConfig_getNodesToCreate(t *testing.T) {
+	cfg := &Config{Name: []string{"k3d-test-gpu"}, ClusterAssociated: "test", Role: "agent", Count: 3}
+
+	nodesToCreate := cfg.getNodesToCreate(1)
+
+	assert.Len(t, nodesToCreate, 2)
+	assert.Equal(t, []string{"k3d-test-gpu-1"}, nodesToCreate[0].Name)
+	assert.Equal(t, []string{"k3d-test-gpu-2"}, nodesToCreate[1].Name)
+
+	for _, nodeToCreate := range nodesToCreate {
+		assert.Equal(t, "test", nodeToCreate.ClusterAssociated)
+	}
+}
+
+func Test_rollbackNodes(t *testing.T) {
+	// the creation of k3d-test-gpu-1 failed after k3d-test-gpu-0 was created.
+	runtime := &stubRuntime{nodes: []*K3D.Node{
+		getTestClusterNode("k3d-test-server-0", K3D.ServerRole),
+		getTestClusterNode("k3d-test-gpu-0", K3D.AgentRole),
+	}}
+
+	cfg := &Config{Name: []string{"k3d-test-gpu"}, ClusterAssociated: "test", Role: "agent", Count: 2}
+
+	rollbackNodes(context.Background(), runtime, cfg.getNodesToCreate(0))
+
+	assert.Equal(t, []string{"k3d-test-gpu-0"}, runtime.deleted)
+	assert.Len(t, runtime.nodes, 1)
+	assert.Equal(t, "k3d-test-server-0", runtime.nodes[0].Name)
+}
+
+func TestConfig_GetFilteredNodesFromClusterWithoutRole(t *testing.T) {
+	runtime := &stubRuntime{nodes: []*K3D.Node{
+		getTestClusterNode("k3d-test-server-0", K3D.ServerRole),
+		getTestClusterNode("k3d-test-agent-0", K3D.AgentRole),
+		getTestClusterNode("k3d-test-serverlb", K3D.LoadBalancerRole),
+	}}
+
+	t.Run("should fetch only the agents when no role is set", func(t *testing.T) {
+		cfg := &Config{ClusterAssociated: "test", All: true}
+
+		nodes, err := cfg.GetFilteredNodesFromCluster(context.Background(), runtime)
+		assert.NoError(t, err)
+		assert.Len(t, nodes, 1)
+		assert.Equal(t, []string{"k3d-test-agent-0"}, nodes[0].Name)
+	})
+
+	t.Run("should fetch the servers when asked for", func(t *testing.T) {
+		cfg := &Config{ClusterAssociated: "test", Role: string(K3D.ServerRole), All: true}
+
+		nodes, err := cfg.GetFilteredNodesFromCluster(context.Background(), runtime)
+		assert.NoError(t, err)
+		assert.Len(t, nodes, 1)
+		assert.Equal(t, []string{"k3d-test-server-0"}, nodes[0].Name)
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	terraformErrors "github.com/nikhilsbhat/terraform-provider-k3d/pkg/errors"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/k3d/cluster"
 	"github.com/rancher/k3d/v5/pkg/client"
 	"github.com/rancher/k3d/v5/pkg/runtimes"
 	K3D "github.com/rancher/k3d/v5/pkg/types"
@@ -13,6 +14,10 @@ import (
 )
 
 // DeleteNodesFromCluster deletes the specified node.
+// When Drain is set, every node is drained before its container is deleted and its kubernetes node object is removed afterwards,
+// the nodes that fail to be drained are left running.
+// When KubeClient is set, the servers are removed from embedded etcd before their containers are deleted,
+// the servers that fail to be removed from etcd are left running.
 func (cfg *Config) DeleteNodesFromCluster(ctx context.Context, runtime runtimes.Runtime) error {
 	nodeLabel := map[string]string{
 		"k3d.cluster": cfg.ClusterAssociated,
@@ -34,8 +39,32 @@ func (cfg *Config) DeleteNodesFromCluster(ctx context.Context, runtime runtimes.
 	errors := make([]string, 0)
 
 	for _, filteredNode := range filteredNodes {
+		if cfg.Drain != nil {
+			if drainErr := cfg.Drain.Drain(ctx, filteredNode.Name); drainErr != nil {
+				errors = append(errors, drainErr.Error())
+
+				continue
+			}
+		}
+
+		if cfg.KubeClient != nil && filteredNode.Role == K3D.ServerRole {
+			if rmErr := cluster.RemoveEtcdMember(ctx, cfg.KubeClient, filteredNode.Name); rmErr != nil {
+				errors = append(errors, rmErr.Error())
+
+				continue
+			}
+		}
+
 		if delErr := client.NodeDelete(ctx, runtime, filteredNode, deleteOps); delErr != nil {
 			errors = append(errors, delErr.Error())
+
+			continue
+		}
+
+		if cfg.Drain != nil {
+			if delErr := cluster.DeleteKubeNode(ctx, cfg.Drain.KubeClient, filteredNode.Name); delErr != nil {
+				errors = append(errors, fmt.Sprintf("removing kubernetes node '%s' errored with: %v", filteredNode.Name, delErr))
+			}
 		}
 	}
 
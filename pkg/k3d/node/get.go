@@ -2,25 +2,64 @@ package node
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/utils"
 	"github.com/rancher/k3d/v5/pkg/runtimes"
 	K3D "github.com/rancher/k3d/v5/pkg/types"
+	"github.com/rancher/k3d/v5/pkg/types/k3s"
+	"github.com/rancher/k3d/v5/pkg/util"
 	"github.com/thoas/go-funk"
 )
 
+const (
+	// K3sNodeLabelArg is the k3s argument that registers the node with a node label.
+	K3sNodeLabelArg = "--node-label"
+	// K3sNodeTaintArg is the k3s argument that registers the node with a node taint.
+	K3sNodeTaintArg = "--node-taint"
+	// K3sTLSSANArg is the k3s argument that adds a subject alternative name to the certificate of the kubernetes API.
+	K3sTLSSANArg = "--tls-san"
+)
+
+// k3dManagedEnvs are the environment variables set on the nodes by k3d itself.
+var k3dManagedEnvs = []string{
+	k3s.EnvClusterToken,
+	k3s.EnvClusterConnectURL,
+	k3s.EnvKubeconfigOutput,
+	K3D.K3dEnvFixCgroupV2,
+	K3D.K3dEnvFixDNS,
+}
+
+// k3dManagedArgs are the k3s arguments added to the nodes by k3d itself, along with the number of values each of them takes.
+var k3dManagedArgs = map[string]int{
+	"--cluster-init": 0,
+	K3sTLSSANArg:     1,
+}
+
 // GetFilteredNodesFromCluster returns the fetched all nodes from a specified cluster with list of *Config type.
+// Only the nodes with the role of the config are returned, the agents when no role is set.
 func (cfg *Config) GetFilteredNodesFromCluster(ctx context.Context, runtime runtimes.Runtime) ([]*Config, error) {
-	cfg.Labels = map[string]string{
-		"k3d.role":    "agent",
-		"k3d.cluster": cfg.ClusterAssociated,
-	}
+	cfg.Labels = cfg.getClusterNodeLabels()
+
+	var (
+		nodes []*Config
+		err   error
+	)
 
 	if cfg.All {
-		return cfg.GetNodesByLabels(ctx, runtime)
+		nodes, err = cfg.GetNodesByLabels(ctx, runtime)
+	} else {
+		nodes, err = cfg.GetFilteredNodes(ctx, runtime)
+	}
+
+	if err != nil {
+		return nil, err
 	}
 
-	return cfg.GetFilteredNodes(ctx, runtime)
+	return funk.Filter(nodes, func(node *Config) bool {
+		return isK3sNode(K3D.Role(node.Role))
+	}).([]*Config), nil
 }
 
 // GetFilteredNodes returns the fetched list of specified nodes from specified cluster with list of *Config type.
@@ -46,6 +85,8 @@ func (cfg *Config) GetNodesByLabels(ctx context.Context, runtime runtimes.Runtim
 
 	filteredNodes := make([]*Config, 0)
 	for _, node := range k3dNodes {
+		k3sArgs, k3sNodeLabels, k3sNodeTaints := GetK3sNodeSettings(node.Cmd)
+
 		filteredNodes = append(filteredNodes, &Config{
 			Name:                 []string{node.Name},
 			Role:                 string(node.Role),
@@ -57,6 +98,9 @@ func (cfg *Config) GetNodesByLabels(ctx context.Context, runtime runtimes.Runtim
 			Networks:             node.Networks,
 			EnvironmentVariables: node.Env,
 			Image:                node.Image,
+			K3sNodeLabels:        k3sNodeLabels,
+			K3sNodeTaints:        k3sNodeTaints,
+			K3sArgs:              k3sArgs,
 			// dropping PortMapping as terraform schema format is yet to be figured.
 			// PortMapping:          getPortMaps(node.Ports),
 		})
@@ -88,7 +132,7 @@ func (cfg *Config) GetNodeStatus(ctx context.Context, runtime runtimes.Runtime)
 
 // GetNodeFromConfig returns K3D.Node equivalent for an stance of Config.
 func (cfg *Config) GetNodeFromConfig() *K3D.Node {
-	return &K3D.Node{
+	node := &K3D.Node{
 		Name: cfg.Name[0],
 		Role: K3D.NodeRoles[cfg.Role],
 		RuntimeLabels: map[string]string{
@@ -96,8 +140,125 @@ func (cfg *Config) GetNodeFromConfig() *K3D.Node {
 			utils.TerraformK3dLabel:        cfg.Created,
 			utils.TerraformCreatedK3dLabel: "true",
 		},
-		Image:   cfg.Image,
-		Memory:  cfg.Memory,
-		Restart: true,
+		Image:         cfg.Image,
+		Memory:        cfg.Memory,
+		Volumes:       cfg.Volumes,
+		Env:           cfg.EnvironmentVariables,
+		Args:          cfg.GetK3sNodeArgs(),
+		K3sNodeLabels: cfg.K3sNodeLabels,
+		Restart:       true,
+	}
+
+	if len(cfg.Pool) != 0 {
+		node.RuntimeLabels[utils.TerraformNodePoolLabel] = cfg.Pool
+	}
+
+	return node
+}
+
+// GetK3sNodeArgs returns the k3s arguments that register the node with the taints of the config, followed by the extra k3s arguments.
+// The node labels are not part of it, k3d adds them to the arguments from the K3sNodeLabels of the node.
+func (cfg *Config) GetK3sNodeArgs() []string {
+	args := make([]string, 0, len(cfg.K3sNodeTaints)+len(cfg.K3sArgs))
+
+	for _, taint := range cfg.K3sNodeTaints {
+		args = append(args, fmt.Sprintf("%s=%s", K3sNodeTaintArg, taint))
+	}
+
+	return append(args, cfg.K3sArgs...)
+}
+
+// GetOwnSettings returns the config of the node with only the node labels, node taints, extra k3s arguments, environment variables
+// and volumes the node was created with, leaving out the environment variables of its image and the ones k3d sets on the node,
+// along with the volumes k3d mounts into the node.
+func (cfg *Config) GetOwnSettings(imageEnv []string) *Config {
+	return &Config{
+		K3sArgs:       cfg.K3sArgs,
+		K3sNodeLabels: cfg.K3sNodeLabels,
+		K3sNodeTaints: cfg.K3sNodeTaints,
+		EnvironmentVariables: funk.FilterString(cfg.EnvironmentVariables, func(env string) bool {
+			return !funk.ContainsString(imageEnv, env) && !IsK3dManagedEnv(env)
+		}),
+		Volumes: funk.FilterString(cfg.Volumes, func(volume string) bool {
+			return !strings.HasSuffix(volume, ":"+K3D.DefaultImageVolumeMountPath) && !isK3dMemoryVolume(volume)
+		}),
 	}
 }
+
+// HasOwnSettings checks if the nodes are set up with settings of their own,
+// in which case they are joined to the cluster with only those rather than the ones copied from an existing node.
+func (cfg *Config) HasOwnSettings() bool {
+	return len(cfg.Pool) != 0 || len(cfg.K3sArgs) != 0 || len(cfg.K3sNodeLabels) != 0 || len(cfg.K3sNodeTaints) != 0 ||
+		len(cfg.EnvironmentVariables) != 0 || len(cfg.Volumes) != 0
+}
+
+// getClusterNodeLabels returns the labels that select the nodes of the cluster with the role of the config,
+// defaulting to the agents so that servers are only picked when asked for.
+func (cfg *Config) getClusterNodeLabels() map[string]string {
+	role := cfg.Role
+	if len(role) == 0 {
+		role = string(K3D.AgentRole)
+	}
+
+	return map[string]string{
+		K3dClusterNameLabel: cfg.ClusterAssociated,
+		K3D.LabelRole:       role,
+	}
+}
+
+// isK3sNode checks if the node of the role runs k3s, rather than being a helper node like the loadbalancer or registry.
+func isK3sNode(role K3D.Role) bool {
+	return role == K3D.ServerRole || role == K3D.AgentRole
+}
+
+// GetK3sNodeSettings splits the command the node runs into the extra k3s arguments, node labels and node taints,
+// skipping the k3s subcommand and the arguments k3d adds by itself. Labels and taints are read both when passed with the flag
+// and as a separate value.
+func GetK3sNodeSettings(cmd []string) ([]string, map[string]string, []string) {
+	k3sArgs := make([]string, 0)
+	k3sNodeLabels := make(map[string]string)
+	k3sNodeTaints := make([]string, 0)
+
+	for index := 1; index < len(cmd); index++ {
+		if skip, ok := k3dManagedArgs[cmd[index]]; ok {
+			index += skip
+
+			continue
+		}
+
+		arg, value, hasValue := strings.Cut(cmd[index], "=")
+
+		if (arg == K3sNodeLabelArg || arg == K3sNodeTaintArg) && !hasValue {
+			if index+1 >= len(cmd) {
+				break
+			}
+
+			index++
+			value = cmd[index]
+		}
+
+		switch arg {
+		case K3sNodeLabelArg:
+			key, val, _ := strings.Cut(value, "=")
+			k3sNodeLabels[key] = val
+		case K3sNodeTaintArg:
+			k3sNodeTaints = append(k3sNodeTaints, value)
+		default:
+			k3sArgs = append(k3sArgs, cmd[index])
+		}
+	}
+
+	return k3sArgs, k3sNodeLabels, k3sNodeTaints
+}
+
+// isK3dMemoryVolume checks if the volume is one of fake meminfo and edac, which k3d mounts when the memory of the node is limited.
+func isK3dMemoryVolume(volume string) bool {
+	return strings.Contains(volume, ":"+util.MemInfoPath) || strings.Contains(volume, ":"+util.EdacFolderPath)
+}
+
+// IsK3dManagedEnv checks if the environment variable, in the format KEY=VALUE, is set on the node by k3d itself.
+func IsK3dManagedEnv(env string) bool {
+	key, _, _ := strings.Cut(env, "=")
+
+	return funk.ContainsString(k3dManagedEnvs, key)
+}
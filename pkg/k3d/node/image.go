@@ -0,0 +1,29 @@
+package node
+
+import "context"
+
+// Image holds the reference of the image a node was created from along with the environment variables baked into it.
+type Image struct {
+	Reference string
+	Env       []string
+}
+
+// GetNodeImage inspects the container backing the node to get the image reference, since k3d reports only the image ID.
+func (inspector *Inspector) GetNodeImage(ctx context.Context, node string) (*Image, error) {
+	containerDetails, err := inspector.client.ContainerInspect(ctx, node)
+	if err != nil {
+		return nil, err
+	}
+
+	imageDetails, _, err := inspector.client.ImageInspectWithRaw(ctx, containerDetails.Image)
+	if err != nil {
+		return nil, err
+	}
+
+	image := &Image{Reference: containerDetails.Config.Image}
+	if imageDetails.Config != nil {
+		image.Env = imageDetails.Config.Env
+	}
+
+	return image, nil
+}
@@ -0,0 +1,40 @@
+package node
+
+import (
+	"context"
+
+	dockerClient "github.com/docker/docker/client"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/client"
+	"github.com/rancher/k3d/v5/pkg/runtimes"
+)
+
+// Inspector inspects the containers backing the nodes for the details k3d does not report, through a single client
+// of the runtime shared by all the nodes inspected. It should be closed once done with the nodes.
+type Inspector struct {
+	client dockerClient.APIClient
+}
+
+// NewInspector returns the Inspector of the nodes of the runtime.
+func NewInspector(runtime runtimes.Runtime) (*Inspector, error) {
+	runtimeClient, err := client.GetDockerClient(runtime)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Inspector{client: runtimeClient}, nil
+}
+
+// Close closes the client of the runtime.
+func (inspector *Inspector) Close() error {
+	return inspector.client.Close()
+}
+
+// GetNodeLabels returns the labels of the container backing the node, since k3d reports only the labels set by itself.
+func (inspector *Inspector) GetNodeLabels(ctx context.Context, node string) (map[string]string, error) {
+	containerDetails, err := inspector.client.ContainerInspect(ctx, node)
+	if err != nil {
+		return nil, err
+	}
+
+	return containerDetails.Config.Labels, nil
+}
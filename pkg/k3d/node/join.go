@@ -0,0 +1,166 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	terraformErrors "github.com/nikhilsbhat/terraform-provider-k3d/pkg/errors"
+	"github.com/rancher/k3d/v5/pkg/actions"
+	"github.com/rancher/k3d/v5/pkg/client"
+	"github.com/rancher/k3d/v5/pkg/runtimes"
+	runtimeErrors "github.com/rancher/k3d/v5/pkg/runtimes/errors"
+	K3D "github.com/rancher/k3d/v5/pkg/types"
+	"github.com/rancher/k3d/v5/pkg/types/k3s"
+)
+
+const (
+	// clusterLabelPrefix is the prefix of the runtime labels that describe the cluster the node is part of.
+	clusterLabelPrefix = "k3d.cluster"
+	// serverAPILabelPrefix is the prefix of the runtime labels that describe how the kubernetes API of the servers is exposed.
+	serverAPILabelPrefix = "k3d.server.api"
+	// tarHeaderSize is the size of the header of the tar archive the runtime returns the files read from the node in.
+	tarHeaderSize = 512
+)
+
+// JoinNodes creates the nodes and joins them to the cluster with only the settings specified on them.
+// Unlike k3d, which copies the command, environment variables and volumes of an existing node with the same role
+// on to the new node, only the settings that make the node part of the cluster are taken from the cluster,
+// so that the nodes do not inherit the settings of the other nodes.
+// Servers join the embedded etcd of the cluster through the existing servers and are put behind the loadbalancer of the cluster.
+func JoinNodes(ctx context.Context, runtime runtimes.Runtime, cluster *K3D.Cluster, nodes []*K3D.Node, opts K3D.NodeCreateOpts) error {
+	k3dCluster, err := client.ClusterGet(ctx, runtime, cluster)
+	if err != nil {
+		return fmt.Errorf("fetching cluster '%s' errored with: %w", cluster.Name, err)
+	}
+
+	servers := client.NodeFilterByRoles(k3dCluster.Nodes, []K3D.Role{K3D.ServerRole}, nil)
+	if len(servers) == 0 {
+		return fmt.Errorf("%w: '%s'", terraformErrors.ErrMinimumServers, k3dCluster.Name)
+	}
+
+	nodeHooks := make([]K3D.NodeHook, 0)
+
+	if registryConfig := getRegistryConfig(ctx, runtime, servers[0]); len(registryConfig) != 0 {
+		nodeHooks = append(nodeHooks, K3D.NodeHook{
+			Stage: K3D.LifecycleStagePreStart,
+			Action: actions.WriteFileAction{
+				Runtime:     runtime,
+				Content:     registryConfig,
+				Dest:        K3D.DefaultRegistriesFilePath,
+				Mode:        0o644,
+				Description: "Write Registry Configuration",
+			},
+		})
+	}
+
+	if k3dCluster.Network.Name != "host" {
+		envInfo, err := client.GatherEnvironmentInfo(ctx, runtime, k3dCluster)
+		if err != nil {
+			return fmt.Errorf("gathering environment info of cluster '%s' errored with: %w", k3dCluster.Name, err)
+		}
+
+		nodeHooks = append(nodeHooks, K3D.NodeHook{
+			Stage: K3D.LifecycleStagePostStart,
+			Action: actions.ExecAction{
+				Runtime: runtime,
+				Command: []string{
+					"sh", "-c",
+					fmt.Sprintf("echo '%s %s' >> /etc/hosts", envInfo.HostGateway.String(), K3D.DefaultK3dInternalHostRecord),
+				},
+				Description: fmt.Sprintf("Inject /etc/hosts record for %s", K3D.DefaultK3dInternalHostRecord),
+			},
+		})
+	}
+
+	opts.NodeHooks = append(opts.NodeHooks, nodeHooks...)
+
+	serversJoined := false
+
+	for _, node := range nodes {
+		joinNode := getJoinNode(node, servers[0], k3dCluster)
+
+		if err = client.NodeRun(ctx, runtime, joinNode, opts); err != nil {
+			return fmt.Errorf("joining node '%s' to cluster '%s' errored with: %w", joinNode.Name, k3dCluster.Name, err)
+		}
+
+		serversJoined = serversJoined || joinNode.Role == K3D.ServerRole
+	}
+
+	if !serversJoined || k3dCluster.ServerLoadBalancer == nil || k3dCluster.ServerLoadBalancer.Node == nil {
+		return nil
+	}
+
+	if err = client.UpdateLoadbalancerConfig(ctx, runtime, k3dCluster); err != nil && !errors.Is(err, client.ErrLBConfigHostNotFound) {
+		return fmt.Errorf("updating loadbalancer of cluster '%s' with the servers joined errored with: %w", k3dCluster.Name, err)
+	}
+
+	return nil
+}
+
+// getJoinNode returns the node with the labels, environment variables and network that make it join the cluster of the server,
+// along with the image volume of the cluster, if any. Servers additionally carry the labels of how the kubernetes API is exposed.
+func getJoinNode(node, server *K3D.Node, k3dCluster *K3D.Cluster) *K3D.Node {
+	joinNode := *node
+
+	joinNode.RuntimeLabels = make(map[string]string)
+
+	for key, value := range server.RuntimeLabels {
+		if strings.HasPrefix(key, clusterLabelPrefix) || (node.Role == K3D.ServerRole && strings.HasPrefix(key, serverAPILabelPrefix)) {
+			joinNode.RuntimeLabels[key] = value
+		}
+	}
+
+	for key, value := range node.RuntimeLabels {
+		joinNode.RuntimeLabels[key] = value
+	}
+
+	joinNode.RuntimeLabels[K3D.LabelRole] = string(node.Role)
+	joinNode.RuntimeLabels[K3D.LabelClusterToken] = k3dCluster.Token
+
+	if len(joinNode.Cmd) == 0 {
+		joinNode.Cmd = K3D.DefaultRoleCmds[node.Role]
+	}
+
+	joinNode.Env = append([]string{
+		fmt.Sprintf("%s=%s", k3s.EnvClusterConnectURL, server.RuntimeLabels[K3D.LabelClusterURL]),
+		fmt.Sprintf("%s=%s", k3s.EnvClusterToken, k3dCluster.Token),
+	}, node.Env...)
+
+	if imageVolume, ok := server.RuntimeLabels[K3D.LabelImageVolume]; ok {
+		joinNode.Volumes = append([]string{fmt.Sprintf("%s:%s", imageVolume, K3D.DefaultImageVolumeMountPath)}, node.Volumes...)
+	}
+
+	joinNode.Networks = []string{k3dCluster.Network.Name}
+	joinNode.Restart = true
+
+	return &joinNode
+}
+
+// getRegistryConfig reads the registries config of the server, so that the agents joined could pull from the same registries.
+// Failing to read it is not fatal, the agents would then pull the images from the upstream registries.
+func getRegistryConfig(ctx context.Context, runtime runtimes.Runtime, server *K3D.Node) []byte {
+	reader, err := runtime.ReadFromNode(ctx, K3D.DefaultRegistriesFilePath, server)
+	if err != nil {
+		if !errors.Is(err, runtimeErrors.ErrRuntimeFileNotFound) {
+			log.Printf("reading registry config from node '%s' errored with: %v", server.Name, err)
+		}
+
+		return nil
+	}
+
+	defer reader.Close()
+
+	registryConfig, err := io.ReadAll(reader)
+	if err != nil || len(registryConfig) < tarHeaderSize {
+		log.Printf("reading registry config from node '%s' errored with: %v", server.Name, err)
+
+		return nil
+	}
+
+	return bytes.Trim(registryConfig[tarHeaderSize:], "\x00")
+}
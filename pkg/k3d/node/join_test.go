@@ -0,0 +1,162 @@
+//nolint:testpackage
+package node
+
+import (
+	"testing"
+
+	K3D "github.com/rancher/k3d/v5/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_getJoinNode(t *testing.T) {
+	server := &K3D.Node{
+		Name: "k3d-test-server-0",
+		Role: K3D.ServerRole,
+		RuntimeLabels: map[string]string{
+			K3D.LabelClusterName:      "test",
+			K3D.LabelClusterURL:       "https://k3d-test-server-0:6443",
+			K3D.LabelImageVolume:      "k3d-test-images",
+			K3D.LabelRole:             "server",
+			K3D.LabelServerAPIHostIP:  "0.0.0.0",
+			"k3d.terraform.unrelated": "true",
+		},
+	}
+
+	cfg := &Config{
+		Name:                 []string{"k3d-test-gpu-0"},
+		Role:                 "agent",
+		Image:                "rancher/k3s:v1.24.4-k3s1",
+		Pool:                 "gpu",
+		EnvironmentVariables: []string{"LOG=debug"},
+		Volumes:              []string{"/data:/data:ro"},
+		K3sNodeLabels:        map[string]string{"tier": "gpu", "accelerator": "nvidia"},
+		K3sNodeTaints:        []string{"gpu=true:NoSchedule"},
+	}
+
+	joinNode := getJoinNode(cfg.GetNodeFromConfig(), server, &K3D.Cluster{
+		Name:    "test",
+		Token:   "secret",
+		Network: K3D.ClusterNetwork{Name: "k3d-test"},
+	})
+
+	assert.Equal(t, []string{"agent"}, joinNode.Cmd)
+	assert.Equal(t, []string{"--node-taint=gpu=true:NoSchedule"}, joinNode.Args)
+	assert.Equal(t, map[string]string{"tier": "gpu", "accelerator": "nvidia"}, joinNode.K3sNodeLabels)
+	assert.Equal(t, []string{"K3S_URL=https://k3d-test-server-0:6443", "K3S_TOKEN=secret", "LOG=debug"}, joinNode.Env)
+	assert.Equal(t, []string{"k3d-test-images:/k3d/images", "/data:/data:ro"}, joinNode.Volumes)
+	assert.Equal(t, []string{"k3d-test"}, joinNode.Networks)
+	assert.Equal(t, "agent", joinNode.RuntimeLabels[K3D.LabelRole])
+	assert.Equal(t, "test", joinNode.RuntimeLabels[K3D.LabelClusterName])
+	assert.Equal(t, "secret", joinNode.RuntimeLabels[K3D.LabelClusterToken])
+	assert.Equal(t, "gpu", joinNode.RuntimeLabels["k3d.terraform.pool"])
+	assert.NotContains(t, joinNode.RuntimeLabels, K3D.LabelServerAPIHostIP)
+	assert.NotContains(t, joinNode.RuntimeLabels, "k3d.terraform.unrelated")
+}
+
+func Test_GetK3sNodeSettings(t *testing.T) {
+	t.Run("should split the command of the node into k3s args, node labels and taints", func(t *testing.T) {
+		cmd := []string{
+			"agent",
+			"--node-taint=gpu=true:NoSchedule",
+			"--kubelet-arg=max-pods=50",
+			"--node-label", "tier=gpu",
+			"--node-label=accelerator=nvidia",
+			"--node-taint", "dedicated=ml:NoExecute",
+		}
+
+		k3sArgs, k3sNodeLabels, k3sNodeTaints := GetK3sNodeSettings(cmd)
+		assert.Equal(t, []string{"--kubelet-arg=max-pods=50"}, k3sArgs)
+		assert.Equal(t, map[string]string{"tier": "gpu", "accelerator": "nvidia"}, k3sNodeLabels)
+		assert.Equal(t, []string{"gpu=true:NoSchedule", "dedicated=ml:NoExecute"}, k3sNodeTaints)
+	})
+
+	t.Run("should skip the arguments added by k3d", func(t *testing.T) {
+		cmd := []string{"server", "--tls-san", "0.0.0.0", "--cluster-init", "--tls-san=api.example.com", "--node-label", "tier=control"}
+
+		k3sArgs, k3sNodeLabels, k3sNodeTaints := GetK3sNodeSettings(cmd)
+		assert.Equal(t, []string{"--tls-san=api.example.com"}, k3sArgs)
+		assert.Equal(t, map[string]string{"tier": "control"}, k3sNodeLabels)
+		assert.Empty(t, k3sNodeTaints)
+	})
+
+	t.Run("should return empty settings when the node runs no command", func(t *testing.T) {
+		k3sArgs, k3sNodeLabels, k3sNodeTaints := GetK3sNodeSettings(nil)
+		assert.Empty(t, k3sArgs)
+		assert.Empty(t, k3sNodeLabels)
+		assert.Empty(t, k3sNodeTaints)
+	})
+}
+
+func TestConfig_HasOwnSettings(t *testing.T) {
+	t.Run("should be false when only the memory and image of the node are set", func(t *testing.T) {
+		cfg := &Config{Image: "rancher/k3s:v1.24.4-k3s1", Memory: "1g"}
+		assert.False(t, cfg.HasOwnSettings())
+	})
+
+	t.Run("should be true when the node sets k3s args of its own", func(t *testing.T) {
+		cfg := &Config{K3sArgs: []string{"--kubelet-arg=max-pods=50"}}
+		assert.True(t, cfg.HasOwnSettings())
+		assert.Equal(t, []string{"--kubelet-arg=max-pods=50"}, cfg.GetK3sNodeArgs())
+	})
+}
+
+func TestConfig_GetOwnSettings(t *testing.T) {
+	t.Run("should leave out the settings of the image and the ones k3d sets on the node", func(t *testing.T) {
+		cfg := &Config{
+			K3sArgs:       []string{"--kubelet-arg=max-pods=50"},
+			K3sNodeLabels: map[string]string{"tier": "gpu"},
+			K3sNodeTaints: []string{"dedicated=gpu:NoSchedule"},
+			EnvironmentVariables: []string{
+				"K3S_URL=https://k3d-test-server-0:6443", "K3S_TOKEN=secret", "LOG=debug",
+				"K3S_KUBECONFIG_OUTPUT=/output/kubeconfig.yaml", "PATH=/bin",
+			},
+			Volumes: []string{
+				"k3d-test-images:/k3d/images", "/opt/models:/models:ro",
+				"/tmp/.k3d/meminfo-k3d-test-gpu-0:/proc/meminfo:ro", "/tmp/.k3d/edac:/sys/devices/system/edac:ro",
+			},
+		}
+
+		settings := cfg.GetOwnSettings([]string{"PATH=/bin"})
+		assert.Equal(t, []string{"--kubelet-arg=max-pods=50"}, settings.K3sArgs)
+		assert.Equal(t, map[string]string{"tier": "gpu"}, settings.K3sNodeLabels)
+		assert.Equal(t, []string{"dedicated=gpu:NoSchedule"}, settings.K3sNodeTaints)
+		assert.Equal(t, []string{"LOG=debug"}, settings.EnvironmentVariables)
+		assert.Equal(t, []string{"/opt/models:/models:ro"}, settings.Volumes)
+	})
+}
+
+func Test_getJoinNodeServer(t *testing.T) {
+	server := &K3D.Node{
+		Name: "k3d-test-server-0",
+		Role: K3D.ServerRole,
+		RuntimeLabels: map[string]string{
+			K3D.LabelClusterName:     "test",
+			K3D.LabelClusterURL:      "https://k3d-test-server-0:6443",
+			K3D.LabelRole:            "server",
+			K3D.LabelServerAPIPort:   "6443",
+			K3D.LabelServerAPIHostIP: "0.0.0.0",
+			K3D.LabelServerIsInit:    "true",
+		},
+	}
+
+	cfg := &Config{
+		Name:    []string{"k3d-test-control-0"},
+		Role:    "server",
+		Image:   "rancher/k3s:v1.24.4-k3s1",
+		K3sArgs: []string{"--etcd-snapshot-retention=3"},
+	}
+
+	joinNode := getJoinNode(cfg.GetNodeFromConfig(), server, &K3D.Cluster{
+		Name:    "test",
+		Token:   "secret",
+		Network: K3D.ClusterNetwork{Name: "k3d-test"},
+	})
+
+	assert.Equal(t, []string{"server"}, joinNode.Cmd)
+	assert.Equal(t, []string{"--etcd-snapshot-retention=3"}, joinNode.Args)
+	assert.Equal(t, []string{"K3S_URL=https://k3d-test-server-0:6443", "K3S_TOKEN=secret"}, joinNode.Env)
+	assert.Equal(t, "server", joinNode.RuntimeLabels[K3D.LabelRole])
+	assert.Equal(t, "6443", joinNode.RuntimeLabels[K3D.LabelServerAPIPort])
+	assert.Equal(t, "0.0.0.0", joinNode.RuntimeLabels[K3D.LabelServerAPIHostIP])
+	assert.NotContains(t, joinNode.RuntimeLabels, K3D.LabelServerIsInit)
+}
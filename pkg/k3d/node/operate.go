@@ -32,19 +32,19 @@ func FilteredNodes(ctx context.Context, runtime runtimes.Runtime, nodes []string
 	return filteredNodes, nil
 }
 
+// StartStopNode starts or stops the nodes of the cluster with the role of the config as per the action, the agents when no role is set.
 func (cfg *Config) StartStopNode(ctx context.Context, runtime runtimes.Runtime) error {
-	nodes, err := runtime.GetNodesByLabel(ctx, map[string]string{
-		"k3d.cluster": cfg.ClusterAssociated,
-		"k3d.role":    "agent",
-	})
+	nodes, err := runtime.GetNodesByLabel(ctx, cfg.getClusterNodeLabels())
 	if err != nil {
 		return err
 	}
 
-	filteredNodes := make([]*K3D.Node, 0)
+	filteredNodes := funk.Filter(nodes, func(node *K3D.Node) bool {
+		return isK3sNode(node.Role)
+	}).([]*K3D.Node)
 
 	if !cfg.All {
-		filteredNodes = funk.Filter(nodes, func(node *K3D.Node) bool {
+		filteredNodes = funk.Filter(filteredNodes, func(node *K3D.Node) bool {
 			return funk.Contains(cfg.Name, node.Name)
 		}).([]*K3D.Node)
 	}
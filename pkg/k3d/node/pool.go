@@ -0,0 +1,42 @@
+package node
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+
+	terraformErrors "github.com/nikhilsbhat/terraform-provider-k3d/pkg/errors"
+)
+
+var (
+	// nodePoolNameRegex is the pattern the names of the node pools should match, as they are part of the names of the nodes.
+	nodePoolNameRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+	// taintRegex is the pattern of the taints k3s registers the node with, key[=value]:effect.
+	taintRegex = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9._/-]*[A-Za-z0-9])?(=[A-Za-z0-9._-]*)?:(NoSchedule|PreferNoSchedule|NoExecute)$`)
+	// reservedNodePoolNames are the names k3d uses for the nodes it creates, which would clash with the nodes of the pool.
+	reservedNodePoolNames = []string{"server", "agent", "serverlb", "tools"}
+)
+
+// ValidateNodePoolName validates the name of the node pool, which should be usable in the names of its nodes
+// and should not clash with the nodes k3d creates.
+func ValidateNodePoolName(name string) error {
+	if !nodePoolNameRegex.MatchString(name) {
+		return fmt.Errorf("%w: '%s' should consist of lower case alphanumeric characters or '-'", terraformErrors.ErrInvalidNodePool, name)
+	}
+
+	if slices.Contains(reservedNodePoolNames, name) {
+		return fmt.Errorf("%w: '%s' is used by k3d for its nodes", terraformErrors.ErrInvalidNodePool, name)
+	}
+
+	return nil
+}
+
+// ValidateTaint validates the taint is in the format k3s expects, key[=value]:effect.
+func ValidateTaint(taint string) error {
+	if !taintRegex.MatchString(taint) {
+		return fmt.Errorf("%w: '%s' should be in the format key[=value]:effect, where effect is one of "+
+			"NoSchedule, PreferNoSchedule or NoExecute", terraformErrors.ErrInvalidTaint, taint)
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package node_test
+
+import (
+	"testing"
+
+	terraformErrors "github.com/nikhilsbhat/terraform-provider-k3d/pkg/errors"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/k3d/node"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateNodePoolName(t *testing.T) {
+	t.Run("should accept the names usable in the names of the nodes", func(t *testing.T) {
+		for _, name := range []string{"gpu", "web-1", "a"} {
+			assert.NoError(t, node.ValidateNodePoolName(name))
+		}
+	})
+
+	t.Run("should reject invalid names and the ones used by k3d", func(t *testing.T) {
+		for _, name := range []string{"", "GPU", "web_1", "-web", "web-", "agent", "server", "serverlb", "tools"} {
+			assert.ErrorIs(t, node.ValidateNodePoolName(name), terraformErrors.ErrInvalidNodePool, name)
+		}
+	})
+}
+
+func TestValidateTaint(t *testing.T) {
+	t.Run("should accept the taints in the format key[=value]:effect", func(t *testing.T) {
+		for _, taint := range []string{"gpu=true:NoSchedule", "dedicated:PreferNoSchedule", "example.com/spot=:NoExecute"} {
+			assert.NoError(t, node.ValidateTaint(taint))
+		}
+	})
+
+	t.Run("should reject the taints with invalid format or effect", func(t *testing.T) {
+		for _, taint := range []string{"gpu=true", "gpu=true:Never", ":NoSchedule", "gpu=a b:NoSchedule"} {
+			assert.ErrorIs(t, node.ValidateTaint(taint), terraformErrors.ErrInvalidTaint, taint)
+		}
+	})
+}
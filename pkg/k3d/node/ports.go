@@ -0,0 +1,22 @@
+package node
+
+import (
+	"context"
+
+	"github.com/docker/go-connections/nat"
+)
+
+// GetNodePublishedPorts inspects the container backing the node to get the host ports its ports are actually published to,
+// since k3d reports only the port bindings the node was created with, where the host port could be left to the runtime.
+func (inspector *Inspector) GetNodePublishedPorts(ctx context.Context, node string) (nat.PortMap, error) {
+	containerDetails, err := inspector.client.ContainerInspect(ctx, node)
+	if err != nil {
+		return nil, err
+	}
+
+	if containerDetails.NetworkSettings == nil {
+		return nat.PortMap{}, nil
+	}
+
+	return containerDetails.NetworkSettings.Ports, nil
+}
@@ -0,0 +1,110 @@
+package node
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/utils"
+	"github.com/rancher/k3d/v5/pkg/runtimes"
+	K3D "github.com/rancher/k3d/v5/pkg/types"
+)
+
+// GetIndexedNodes returns names of the nodes of the selected role from the cluster, which follows the naming '<name>-<index>'
+// where name is the first element of Config.Name, sorted by their index.
+// When Config.Created is set, only the nodes created by terraform at that time are considered.
+func (cfg *Config) GetIndexedNodes(ctx context.Context, runtime runtimes.Runtime) ([]string, error) {
+	nodeCfg := Config{Labels: map[string]string{
+		K3dClusterNameLabel: cfg.ClusterAssociated,
+		K3D.LabelRole:       cfg.Role,
+	}}
+
+	if len(cfg.Pool) != 0 {
+		nodeCfg.Labels[utils.TerraformNodePoolLabel] = cfg.Pool
+	}
+
+	if len(cfg.Created) != 0 {
+		nodeCfg.Labels[utils.TerraformK3dLabel] = cfg.Created
+	}
+
+	nodes, err := nodeCfg.GetNodesByLabels(ctx, runtime)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		names = append(names, node.Name[0])
+	}
+
+	return IndexedNodes(cfg.Name[0], names), nil
+}
+
+// ScaleNodes adds or removes the nodes of the selected role so that the cluster ends up with 'desired' number of them.
+// New nodes are indexed after the highest existing index, and nodes with the highest index are removed first.
+func (cfg *Config) ScaleNodes(ctx context.Context, runtime runtimes.Runtime, desired int) error {
+	existingNodes, err := cfg.GetIndexedNodes(ctx, runtime)
+	if err != nil {
+		return err
+	}
+
+	if desired < len(existingNodes) {
+		nodesToDelete := &Config{
+			Name:              existingNodes[desired:],
+			ClusterAssociated: cfg.ClusterAssociated,
+			Drain:             cfg.Drain,
+			KubeClient:        cfg.KubeClient,
+		}
+
+		return nodesToDelete.DeleteNodesFromCluster(ctx, runtime)
+	}
+
+	if desired == len(existingNodes) {
+		return nil
+	}
+
+	startFrom := 0
+	if len(existingNodes) != 0 {
+		startFrom, _ = getNodeIndex(cfg.Name[0], existingNodes[len(existingNodes)-1])
+		startFrom++
+	}
+
+	nodesToCreate := *cfg
+	nodesToCreate.Count = startFrom + desired - len(existingNodes)
+
+	return nodesToCreate.CreateNodes(ctx, runtime, startFrom)
+}
+
+// IndexedNodes filters the nodes that follows the naming '<prefix>-<index>' and returns them sorted by their index.
+func IndexedNodes(prefix string, nodes []string) []string {
+	indexedNodes := make([]string, 0, len(nodes))
+
+	for _, node := range nodes {
+		if _, ok := getNodeIndex(prefix, node); ok {
+			indexedNodes = append(indexedNodes, node)
+		}
+	}
+
+	sort.SliceStable(indexedNodes, func(i, j int) bool {
+		left, _ := getNodeIndex(prefix, indexedNodes[i])
+		right, _ := getNodeIndex(prefix, indexedNodes[j])
+
+		return left < right
+	})
+
+	return indexedNodes
+}
+
+func getNodeIndex(prefix, node string) (int, bool) {
+	if !strings.HasPrefix(node, prefix+"-") {
+		return 0, false
+	}
+
+	index, err := strconv.Atoi(strings.TrimPrefix(node, prefix+"-"))
+	if err != nil {
+		return 0, false
+	}
+
+	return index, true
+}
@@ -0,0 +1,36 @@
+//nolint:testpackage
+package node
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndexedNodes(t *testing.T) {
+	t.Run("should return only the indexed nodes sorted by their index", func(t *testing.T) {
+		nodes := []string{
+			"k3d-test-agent-10",
+			"k3d-test-agent-2",
+			"k3d-test-agent-0",
+			"test-node-from-terraform-0",
+			"k3d-test-agent-extra",
+		}
+
+		expected := []string{"k3d-test-agent-0", "k3d-test-agent-2", "k3d-test-agent-10"}
+		assert.Equal(t, expected, IndexedNodes("k3d-test-agent", nodes))
+	})
+}
+
+func Test_getNodeIndex(t *testing.T) {
+	t.Run("should be able to fetch index of the node", func(t *testing.T) {
+		index, ok := getNodeIndex("k3d-test-server", "k3d-test-server-3")
+		assert.True(t, ok)
+		assert.Equal(t, 3, index)
+	})
+
+	t.Run("should fail to fetch index of nodes not matching the prefix", func(t *testing.T) {
+		_, ok := getNodeIndex("k3d-test-server", "k3d-test-agent-3")
+		assert.False(t, ok)
+	})
+}
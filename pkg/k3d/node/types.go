@@ -4,8 +4,10 @@ import (
 	"context"
 	"time"
 
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/k3d/cluster"
 	"github.com/rancher/k3d/v5/pkg/runtimes"
 	K3D "github.com/rancher/k3d/v5/pkg/types"
+	"k8s.io/client-go/kubernetes"
 )
 
 const (
@@ -22,28 +24,36 @@ type K3DNode interface {
 	GetNodesByLabels(ctx context.Context, runtime runtimes.Runtime) ([]*Config, error)
 	GetNodeStatus(ctx context.Context, runtime runtimes.Runtime) ([]*Status, error)
 	GetNodeFromConfig() *K3D.Node
+	GetIndexedNodes(ctx context.Context, runtime runtimes.Runtime) ([]string, error)
+	ScaleNodes(ctx context.Context, runtime runtimes.Runtime, desired int) error
 	StartStopNode(ctx context.Context, runtime runtimes.Runtime) error
 }
 
 // Config stores filtered node data of k3d cluster.
 type Config struct {
-	Name                 []string          `json:"name,omitempty"          mapstructure:"name"`
-	Role                 string            `json:"role,omitempty"          mapstructure:"role"`
-	ClusterAssociated    string            `json:"cluster,omitempty"       mapstructure:"cluster"`
-	State                string            `json:"state,omitempty"         mapstructure:"state"`
-	Created              string            `json:"created,omitempty"       mapstructure:"created"`
-	Memory               string            `json:"memory,omitempty"        mapstructure:"memory"`
-	Volumes              []string          `json:"volumes,omitempty"       mapstructure:"volumes"`
-	Networks             []string          `json:"networks,omitempty"      mapstructure:"networks"`
-	EnvironmentVariables []string          `json:"env,omitempty"           mapstructure:"env"`
-	Count                int               `json:"count,omitempty"         mapstructure:"count"`
-	Image                string            `json:"image,omitempty"         mapstructure:"image"`
-	PortMapping          map[string]any    `json:"port_mappings,omitempty" mapstructure:"port_mappings"`
-	Timeout              time.Duration     `json:"timeout,omitempty"       mapstructure:"timeout"`
-	Wait                 bool              `json:"wait,omitempty"          mapstructure:"wait"`
-	All                  bool              `json:"all,omitempty"           mapstructure:"all"`
-	Labels               map[string]string `json:"labels,omitempty"        mapstructure:"labels"`
-	Action               string            `json:"action,omitempty"        mapstructure:"action"`
+	Name                 []string             `json:"name,omitempty"            mapstructure:"name"`
+	Role                 string               `json:"role,omitempty"            mapstructure:"role"`
+	ClusterAssociated    string               `json:"cluster,omitempty"         mapstructure:"cluster"`
+	State                string               `json:"state,omitempty"           mapstructure:"state"`
+	Created              string               `json:"created,omitempty"         mapstructure:"created"`
+	Memory               string               `json:"memory,omitempty"          mapstructure:"memory"`
+	Volumes              []string             `json:"volumes,omitempty"         mapstructure:"volumes"`
+	Networks             []string             `json:"networks,omitempty"        mapstructure:"networks"`
+	EnvironmentVariables []string             `json:"env,omitempty"             mapstructure:"env"`
+	Count                int                  `json:"count,omitempty"           mapstructure:"count"`
+	Image                string               `json:"image,omitempty"           mapstructure:"image"`
+	PortMapping          map[string]any       `json:"port_mappings,omitempty"   mapstructure:"port_mappings"`
+	Timeout              time.Duration        `json:"timeout,omitempty"         mapstructure:"timeout"`
+	Wait                 bool                 `json:"wait,omitempty"            mapstructure:"wait"`
+	All                  bool                 `json:"all,omitempty"             mapstructure:"all"`
+	Labels               map[string]string    `json:"labels,omitempty"          mapstructure:"labels"`
+	Action               string               `json:"action,omitempty"          mapstructure:"action"`
+	Pool                 string               `json:"pool,omitempty"            mapstructure:"pool"`
+	K3sNodeLabels        map[string]string    `json:"k3s_node_labels,omitempty" mapstructure:"k3s_node_labels"`
+	K3sNodeTaints        []string             `json:"k3s_node_taints,omitempty" mapstructure:"k3s_node_taints"`
+	K3sArgs              []string             `json:"k3s_args,omitempty"        mapstructure:"k3s_args"`
+	Drain                *cluster.DrainConfig `json:"-"                         mapstructure:"-"`
+	KubeClient           kubernetes.Interface `json:"-"                         mapstructure:"-"`
 }
 
 // Status helps to store filtered node status of k3d cluster.
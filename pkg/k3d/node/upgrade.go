@@ -0,0 +1,123 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/k3d/cluster"
+	"github.com/rancher/k3d/v5/pkg/client"
+	"github.com/rancher/k3d/v5/pkg/runtimes"
+	K3D "github.com/rancher/k3d/v5/pkg/types"
+	"github.com/thoas/go-funk"
+	"k8s.io/client-go/kubernetes"
+)
+
+// UpgradeConfig holds the image the nodes are upgraded to, along with how long each upgraded node is waited for to be Ready.
+type UpgradeConfig struct {
+	Image      string
+	KubeClient kubernetes.Interface
+	Timeout    time.Duration
+	Interval   time.Duration
+}
+
+// UpgradeNodes replaces the containers of the nodes one at a time with the ones from the image, servers first and then agents.
+// Every node keeps its volumes, labels and arguments, and is waited for to be Ready before moving to the next one.
+// Nodes already running the image are skipped, so that an upgrade that failed midway could be resumed.
+// It stops at the first node that fails to be upgraded and returns the names of the nodes upgraded until then.
+func (cfg *UpgradeConfig) UpgradeNodes(ctx context.Context, runtime runtimes.Runtime, nodes []*K3D.Node) ([]string, error) {
+	nodesToUpgrade := append([]*K3D.Node{}, nodes...)
+	sort.SliceStable(nodesToUpgrade, func(i, j int) bool {
+		return nodesToUpgrade[i].Role == K3D.ServerRole && nodesToUpgrade[j].Role != K3D.ServerRole
+	})
+
+	inspector, err := NewInspector(runtime)
+	if err != nil {
+		return nil, err
+	}
+
+	defer inspector.Close()
+
+	upgraded := make([]string, 0, len(nodesToUpgrade))
+
+	for _, node := range nodesToUpgrade {
+		name := node.Name
+
+		image, err := inspector.GetNodeImage(ctx, name)
+		if err != nil {
+			return upgraded, fmt.Errorf("fetching image of node '%s' errored with: %w", name, err)
+		}
+
+		if image.Reference == cfg.Image {
+			log.Printf("node '%s' is already running image '%s', skipping it", name, cfg.Image)
+
+			continue
+		}
+
+		labels, err := inspector.GetNodeLabels(ctx, name)
+		if err != nil {
+			return upgraded, fmt.Errorf("fetching labels of node '%s' errored with: %w", name, err)
+		}
+
+		log.Printf("upgrading node '%s' from image '%s' to '%s'", name, image.Reference, cfg.Image)
+
+		since := time.Now()
+
+		if err = client.NodeReplace(ctx, runtime, node, getUpgradedNode(node, cfg.Image, image.Env, labels)); err != nil {
+			return upgraded, fmt.Errorf("replacing node '%s' errored with: %w", name, err)
+		}
+
+		if err = cluster.WaitForNodeReady(ctx, cfg.KubeClient, name, since, cfg.Timeout, cfg.Interval); err != nil {
+			return upgraded, err
+		}
+
+		upgraded = append(upgraded, name)
+	}
+
+	return upgraded, nil
+}
+
+// getUpgradedNode returns the spec of the node to replace the existing node with, running the image.
+// The environment variables baked into the earlier image and the settings k3d adds while creating the node are left out,
+// since k3d adds them again.
+func getUpgradedNode(node *K3D.Node, image string, imageEnv []string, labels map[string]string) *K3D.Node {
+	upgraded := *node
+	upgraded.Image = image
+	upgraded.State = K3D.NodeState{}
+	upgraded.Args = []string{}
+
+	upgraded.RuntimeLabels = make(map[string]string)
+	for key, value := range node.RuntimeLabels {
+		upgraded.RuntimeLabels[key] = value
+	}
+
+	for key, value := range labels {
+		upgraded.RuntimeLabels[key] = value
+	}
+
+	upgraded.Env = funk.FilterString(node.Env, func(env string) bool {
+		return !funk.ContainsString(imageEnv, env) && !funk.ContainsString(K3D.DefaultNodeEnv, env)
+	})
+
+	upgraded.Volumes = funk.FilterString(node.Volumes, func(volume string) bool {
+		return !isK3dMemoryVolume(volume)
+	})
+
+	upgraded.Cmd = make([]string, 0, len(node.Cmd))
+
+	for index := 0; index < len(node.Cmd); index++ {
+		// tls-san of the host of the kubernetes API is added by k3d to the servers.
+		if node.Role == K3D.ServerRole && node.Cmd[index] == "--tls-san" && index+1 < len(node.Cmd) &&
+			node.Cmd[index+1] == node.RuntimeLabels[K3D.LabelServerAPIHost] {
+			index++
+
+			continue
+		}
+
+		upgraded.Cmd = append(upgraded.Cmd, node.Cmd[index])
+	}
+
+	return &upgraded
+}
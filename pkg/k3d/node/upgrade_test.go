@@ -0,0 +1,38 @@
+//nolint:testpackage
+package node
+
+import (
+	"testing"
+
+	K3D "github.com/rancher/k3d/v5/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_getUpgradedNode(t *testing.T) {
+	server := &K3D.Node{
+		Name:  "k3d-test-server-0",
+		Role:  K3D.ServerRole,
+		Image: "sha256:5f5e5b5d",
+		Cmd:   []string{"server", "--tls-san", "0.0.0.0", "--tls-san=api.example.com", "--node-label", "tier=control"},
+		Env:   []string{"K3S_TOKEN=secret", "K3S_KUBECONFIG_OUTPUT=/output/kubeconfig.yaml", "PATH=/bin", "LOG=debug"},
+		Volumes: []string{
+			"k3d-test-images:/k3d/images", "/tmp/data:/data", "/root/.k3d/.k3d-test-server-0/meminfo:/proc/meminfo:ro",
+		},
+		RuntimeLabels: map[string]string{K3D.LabelServerAPIHost: "0.0.0.0", K3D.LabelRole: "server"},
+		State:         K3D.NodeState{Running: true, Status: "running"},
+		Memory:        "1G",
+	}
+
+	upgraded := getUpgradedNode(server, "rancher/k3s:v1.25.6-k3s1", []string{"PATH=/bin"}, map[string]string{"team": "platform"})
+
+	assert.Equal(t, "rancher/k3s:v1.25.6-k3s1", upgraded.Image)
+	assert.Equal(t, []string{"server", "--tls-san=api.example.com", "--node-label", "tier=control"}, upgraded.Cmd)
+	assert.Equal(t, []string{"K3S_TOKEN=secret", "LOG=debug"}, upgraded.Env)
+	assert.Equal(t, []string{"k3d-test-images:/k3d/images", "/tmp/data:/data"}, upgraded.Volumes)
+	assert.Equal(t, map[string]string{K3D.LabelServerAPIHost: "0.0.0.0", K3D.LabelRole: "server", "team": "platform"}, upgraded.RuntimeLabels)
+	assert.Equal(t, K3D.NodeState{}, upgraded.State)
+	assert.Equal(t, "1G", upgraded.Memory)
+
+	assert.Equal(t, "sha256:5f5e5b5d", server.Image)
+	assert.Len(t, server.RuntimeLabels, 2)
+}
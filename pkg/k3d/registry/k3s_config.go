@@ -0,0 +1,63 @@
+package registry
+
+import (
+	"fmt"
+	"net/url"
+
+	terraformErrors "github.com/nikhilsbhat/terraform-provider-k3d/pkg/errors"
+	"github.com/rancher/k3d/v5/pkg/types/k3s"
+	"gopkg.in/yaml.v2"
+)
+
+// RenderK3sRegistries renders the registries config to the registries.yaml understood by k3s.
+func RenderK3sRegistries(registries *k3s.Registry) (string, error) {
+	out, err := yaml.Marshal(registries)
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+// ValidateK3sRegistries validates the registries config and the registries.yaml rendered from it,
+// an error is returned for every invalid entry.
+func ValidateK3sRegistries(registries *k3s.Registry) []error {
+	validationErrors := make([]error, 0)
+
+	for name, mirror := range registries.Mirrors {
+		if len(mirror.Endpoints) == 0 {
+			validationErrors = append(validationErrors, fmt.Errorf("%w: mirror '%s' should have at least one endpoint",
+				terraformErrors.ErrInvalidRegistriesConfig, name))
+		}
+
+		for _, endpoint := range mirror.Endpoints {
+			if endpointURL, err := url.Parse(endpoint); err != nil || len(endpointURL.Scheme) == 0 || len(endpointURL.Host) == 0 {
+				validationErrors = append(validationErrors, fmt.Errorf("%w: endpoint '%s' of mirror '%s' is not a valid url with scheme and host",
+					terraformErrors.ErrInvalidRegistriesConfig, endpoint, name))
+			}
+		}
+	}
+
+	for name, config := range registries.Configs {
+		if config.TLS == nil {
+			continue
+		}
+
+		if (len(config.TLS.CertFile) == 0) != (len(config.TLS.KeyFile) == 0) {
+			validationErrors = append(validationErrors, fmt.Errorf("%w: cert_file and key_file of registry '%s' should be set together",
+				terraformErrors.ErrInvalidRegistriesConfig, name))
+		}
+	}
+
+	rendered, err := RenderK3sRegistries(registries)
+	if err != nil {
+		return append(validationErrors, fmt.Errorf("%w: %v", terraformErrors.ErrInvalidRegistriesConfig, err))
+	}
+
+	var parsed k3s.Registry
+	if err = yaml.UnmarshalStrict([]byte(rendered), &parsed); err != nil {
+		validationErrors = append(validationErrors, fmt.Errorf("%w: %v", terraformErrors.ErrInvalidRegistriesConfig, err))
+	}
+
+	return validationErrors
+}
@@ -0,0 +1,38 @@
+package registry_test
+
+import (
+	"testing"
+
+	terraformErrors "github.com/nikhilsbhat/terraform-provider-k3d/pkg/errors"
+	"github.com/nikhilsbhat/terraform-provider-k3d/pkg/k3d/registry"
+	"github.com/rancher/k3d/v5/pkg/types/k3s"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateK3sRegistries(t *testing.T) {
+	t.Run("should not return errors for valid config", func(t *testing.T) {
+		registries := &k3s.Registry{
+			Mirrors: map[string]k3s.Mirror{"docker.io": {Endpoints: []string{"https://mirror.example.com"}}},
+			Configs: map[string]k3s.RegistryConfig{"mirror.example.com": {
+				TLS: &k3s.TLSConfig{CertFile: "/etc/ssl/cert.pem", KeyFile: "/etc/ssl/key.pem"},
+			}},
+		}
+		assert.Empty(t, registry.ValidateK3sRegistries(registries))
+	})
+
+	t.Run("should return an error for every invalid entry", func(t *testing.T) {
+		registries := &k3s.Registry{
+			Mirrors: map[string]k3s.Mirror{"docker.io": {Endpoints: []string{"mirror.example.com"}}},
+			Configs: map[string]k3s.RegistryConfig{"mirror.example.com": {
+				TLS: &k3s.TLSConfig{CertFile: "/etc/ssl/cert.pem"},
+			}},
+		}
+
+		errs := registry.ValidateK3sRegistries(registries)
+		assert.Len(t, errs, 2)
+
+		for _, err := range errs {
+			assert.ErrorIs(t, err, terraformErrors.ErrInvalidRegistriesConfig)
+		}
+	})
+}
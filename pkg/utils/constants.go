@@ -28,6 +28,11 @@ const (
 	TerraformResourceState            = "state"
 	TerraformResourceRegistries       = "registries"
 	TerraformResourceRegistriesList   = "registries_list"
+	TerraformResourceCreatedRegistry  = "created_registry"
+	TerraformResourceClusterHost      = "host"
+	TerraformResourceClusterCACert    = "cluster_ca_certificate"
+	TerraformResourceClientCert       = "client_certificate"
+	TerraformResourceClientKey        = "client_key"
 	TerraformResourcePorts            = "ports"
 	TerraformResourceHost             = "host"
 	TerraformResourceExpose           = "expose"
@@ -49,17 +54,34 @@ const (
 	TerraformResourceVolumes          = "volumes"
 	TerraformResourceK3dOptions       = "k3d_options"
 	TerraformResourceK3sOptions       = "k3s_options"
+	TerraformResourceK3s              = "k3s"
+	TerraformResourceNodePool         = "node_pool"
+	TerraformResourceNetworkID        = "network_id"
+	TerraformResourceNetworkSubnet    = "subnet"
+	TerraformResourceGateway          = "gateway"
+	TerraformResourceIPRange          = "ip_range"
+	TerraformResourceK3sArgs          = "k3s_args"
+	TerraformResourceTaints           = "taints"
+	TerraformResourceDrain            = "drain"
 	TerraformHostAlias                = "host_aliases"
 	TerraformKubeAPI                  = "kube_api"
 	TerrFormConfigYAML                = "config_yaml"
+	TerraformSimpleConfig             = "simple_config"
+	TerraformSimpleConfigHash         = "simple_config_hash"
+	TerraformWaitFor                  = "wait_for"
+	TerraformAddons                   = "addons"
+	TerraformAddonsHash               = "addons_hash"
 	TerraformK3dLabel                 = "k3d.terraform"
 	TerraformCreatedK3dLabel          = "k3d.terraform.created"
+	TerraformVolumeClusterLabel       = "k3d.terraform.cluster"
+	TerraformNodePoolLabel            = "k3d.terraform.pool"
 	TerraformK3dRegistry              = "registry"
 	TerraformKubernetesVersion        = "kubernetes_version"
 	TerraformK3dAPIVersion            = "k3d_api_version"
 	TerraformK3dKind                  = "kind"
 	TerraformK3dRuntime               = "runtime"
 	TerraformTimeOut5                 = 5
+	TerraformTimeOut10                = 10
 	K3DRepoDEFAULT                    = "rancher/k3s"
 	RegistryConnectedState            = "connected"
 	RegistryDisconnectedState         = "disconnected"
@@ -0,0 +1,12 @@
+package utils
+
+import (
+	"context"
+	"time"
+)
+
+// RollbackContext returns a context to roll back the changes made with ctx.
+// It is detached from the cancellation of ctx, so that the rollback still runs once the deadline of ctx is hit.
+func RollbackContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), TerraformTimeOut5*time.Minute)
+}